@@ -0,0 +1,136 @@
+package introspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// APIChange describes an API present in both outputs whose contract changed
+type APIChange struct {
+	API              string `json:"api"`
+	OldSignature     string `json:"old_signature"`
+	NewSignature     string `json:"new_signature"`
+	SignatureChanged bool   `json:"signature_changed"` // Signature or API type differs
+	NewlyDeprecated  bool   `json:"newly_deprecated"`
+}
+
+// SurfaceDiff is the delta between two introspection outputs
+type SurfaceDiff struct {
+	Library    string      `json:"library"`
+	OldVersion string      `json:"old_version"`
+	NewVersion string      `json:"new_version"`
+	Added      []string    `json:"added"`
+	Removed    []string    `json:"removed"`
+	Changed    []APIChange `json:"changed"`
+}
+
+// apiKey identifies an API across runs by name and signature
+func apiKey(api APIMetadata) string {
+	return api.API + "\x00" + api.Signature
+}
+
+// apiKeys returns the set of apiKey values of apis
+func apiKeys(apis []APIMetadata) map[string]bool {
+	keys := make(map[string]bool, len(apis))
+	for _, api := range apis {
+		keys[apiKey(api)] = true
+	}
+	return keys
+}
+
+// dropKnown keeps only APIs whose apiKey is not in known, as a one-sided
+// diff against a baseline (Options.Baseline)
+func dropKnown(apis []APIMetadata, known map[string]bool) []APIMetadata {
+	kept := apis[:0]
+	for _, api := range apis {
+		if !known[apiKey(api)] {
+			kept = append(kept, api)
+		}
+	}
+	return kept
+}
+
+// LoadOutput reads a JSON file written by a previous run
+func LoadOutput(path string) (IntrospectionOutput, error) {
+	var output IntrospectionOutput
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return output, err
+	}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return output, fmt.Errorf("%s: %w", path, err)
+	}
+	return output, nil
+}
+
+// Diff compares two API surfaces by API name
+func Diff(from, to IntrospectionOutput) SurfaceDiff {
+	diff := SurfaceDiff{
+		Library:    to.Library,
+		OldVersion: from.Version,
+		NewVersion: to.Version,
+		Added:      []string{},
+		Removed:    []string{},
+		Changed:    []APIChange{},
+	}
+
+	before := make(map[string]APIMetadata, len(from.APIs))
+	for _, api := range from.APIs {
+		before[api.API] = api
+	}
+	after := make(map[string]APIMetadata, len(to.APIs))
+	for _, api := range to.APIs {
+		after[api.API] = api
+	}
+
+	for _, api := range to.APIs {
+		prev, ok := before[api.API]
+		if !ok {
+			diff.Added = append(diff.Added, api.API)
+			continue
+		}
+		change := APIChange{
+			API:              api.API,
+			OldSignature:     prev.Signature,
+			NewSignature:     api.Signature,
+			SignatureChanged: prev.Signature != api.Signature || prev.Type != api.Type,
+			NewlyDeprecated:  api.IsDeprecated && !prev.IsDeprecated,
+		}
+		if change.SignatureChanged || change.NewlyDeprecated {
+			diff.Changed = append(diff.Changed, change)
+		}
+	}
+	for _, api := range from.APIs {
+		if _, ok := after[api.API]; !ok {
+			diff.Removed = append(diff.Removed, api.API)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].API < diff.Changed[j].API })
+	return diff
+}
+
+// WriteDiffSummary writes a human-readable report of diff, one line per API
+func WriteDiffSummary(w io.Writer, diff SurfaceDiff) {
+	fmt.Fprintf(w, "%s %s -> %s: %d added, %d removed, %d changed\n",
+		diff.Library, diff.OldVersion, diff.NewVersion, len(diff.Added), len(diff.Removed), len(diff.Changed))
+	for _, api := range diff.Added {
+		fmt.Fprintf(w, "  + %s\n", api)
+	}
+	for _, api := range diff.Removed {
+		fmt.Fprintf(w, "  - %s\n", api)
+	}
+	for _, change := range diff.Changed {
+		if change.SignatureChanged {
+			fmt.Fprintf(w, "  ~ %s: %s -> %s\n", change.API, change.OldSignature, change.NewSignature)
+		}
+		if change.NewlyDeprecated {
+			fmt.Fprintf(w, "  ! %s: deprecated\n", change.API)
+		}
+	}
+}
@@ -0,0 +1,2003 @@
+package introspect
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	"go/constant"
+	"go/doc"
+	"go/doc/comment"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// isExported checks if an identifier is exported (starts with uppercase)
+func isExported(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	return name[0] >= 'A' && name[0] <= 'Z'
+}
+
+// docParagraphs splits comment text into blank-line separated paragraphs
+func docParagraphs(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+
+	var paragraphs []string
+	for _, para := range strings.Split(doc.Text(), "\n\n") {
+		if para = strings.TrimSpace(para); para != "" {
+			paragraphs = append(paragraphs, para)
+		}
+	}
+	return paragraphs
+}
+
+// isDeprecated checks for a "Deprecated:" paragraph, per the Godoc convention
+func isDeprecated(doc *ast.CommentGroup) bool {
+	for _, para := range docParagraphs(doc) {
+		if strings.HasPrefix(para, "Deprecated:") {
+			return true
+		}
+	}
+	return false
+}
+
+// deprecationMessage returns the text of the "Deprecated:" paragraph on one line
+func deprecationMessage(doc *ast.CommentGroup) string {
+	for _, para := range docParagraphs(doc) {
+		if msg, ok := strings.CutPrefix(para, "Deprecated:"); ok {
+			return strings.Join(strings.Fields(msg), " ")
+		}
+	}
+	return ""
+}
+
+// isExperimental checks for an "Experimental:" paragraph, mirroring "Deprecated:"
+func isExperimental(doc *ast.CommentGroup) bool {
+	for _, para := range docParagraphs(doc) {
+		if strings.HasPrefix(para, "Experimental:") {
+			return true
+		}
+	}
+	return false
+}
+
+// stableSince returns the version of a "Stable since v1.2" line, without
+// trailing punctuation
+func stableSince(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "Stable since")
+		if fields := strings.Fields(rest); ok && len(fields) > 0 {
+			return strings.TrimRight(fields[0], ".,;:)")
+		}
+	}
+	return ""
+}
+
+// docSummary returns the first-sentence synopsis Godoc shows for a comment
+func docSummary(comment *ast.CommentGroup) string {
+	if comment == nil {
+		return ""
+	}
+	return new(doc.Package).Synopsis(comment.Text())
+}
+
+// docCodeBlocks returns the indented code blocks of a doc comment, as
+// recognized by the Go 1.19 doc comment syntax
+func docCodeBlocks(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	var blocks []string
+	var parser comment.Parser
+	for _, block := range parser.Parse(doc.Text()).Content {
+		if code, ok := block.(*comment.Code); ok {
+			blocks = append(blocks, code.Text)
+		}
+	}
+	return blocks
+}
+
+// hasDocstring checks if symbol has documentation
+func hasDocstring(doc *ast.CommentGroup) bool {
+	return doc != nil && len(doc.List) > 0
+}
+
+// position returns the file name and line number of pos. Files under baseDir
+// are reported relative to it.
+func position(fset *token.FileSet, pos token.Pos, baseDir string) (string, int) {
+	p := fset.Position(pos)
+	if rel, err := filepath.Rel(baseDir, p.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel), p.Line
+	}
+	return p.Filename, p.Line
+}
+
+// specDoc returns the documentation for a spec within a GenDecl. Grouped
+// blocks document each spec individually; the declaration's own comment
+// only applies when it holds a single spec.
+func specDoc(doc *ast.CommentGroup, decl *ast.GenDecl) *ast.CommentGroup {
+	if doc != nil {
+		return doc
+	}
+	if len(decl.Specs) == 1 {
+		return decl.Doc
+	}
+	return nil
+}
+
+// typeToString renders a type expression as Go source text
+func typeToString(fset *token.FileSet, expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	// Variadic parameters: ...T
+	if ellipsis, ok := expr.(*ast.Ellipsis); ok {
+		return "..." + typeToString(fset, ellipsis.Elt)
+	}
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, expr); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// inferType guesses the type of a value expression from its syntax alone.
+// Returns "" when the type cannot be determined without type checking.
+func inferType(fset *token.FileSet, expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.INT:
+			return "int"
+		case token.FLOAT:
+			return "float64"
+		case token.IMAG:
+			return "complex128"
+		case token.CHAR:
+			return "rune"
+		case token.STRING:
+			return "string"
+		}
+	case *ast.CompositeLit:
+		if e.Type != nil {
+			return typeToString(fset, e.Type)
+		}
+	case *ast.UnaryExpr:
+		if lit, ok := e.X.(*ast.CompositeLit); ok && e.Op == token.AND && lit.Type != nil {
+			return "*" + typeToString(fset, lit.Type)
+		}
+	case *ast.CallExpr:
+		// make(chan<- T) and new(T) name their result type
+		if fun, ok := e.Fun.(*ast.Ident); ok && len(e.Args) > 0 {
+			switch fun.Name {
+			case "make":
+				return typeToString(fset, e.Args[0])
+			case "new":
+				return "*" + typeToString(fset, e.Args[0])
+			}
+		}
+		// Conversions like time.Duration(5) or []byte("x")
+		switch fun := e.Fun.(type) {
+		case *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType, *ast.StructType:
+			return typeToString(fset, fun)
+		case *ast.ParenExpr:
+			if star, ok := fun.X.(*ast.StarExpr); ok {
+				return typeToString(fset, star)
+			}
+		}
+	case *ast.FuncLit:
+		return typeToString(fset, e.Type)
+	}
+	return ""
+}
+
+// literalType returns the type a composite literal value spells out, T for
+// T{...} and *T for &T{...}, or nil for other values
+func literalType(value ast.Expr) ast.Expr {
+	switch e := value.(type) {
+	case *ast.CompositeLit:
+		return e.Type
+	case *ast.UnaryExpr:
+		if lit, ok := e.X.(*ast.CompositeLit); ok && e.Op == token.AND && lit.Type != nil {
+			return &ast.StarExpr{Star: e.Pos(), X: lit.Type}
+		}
+	}
+	return nil
+}
+
+// typeParamsToString renders a type parameter list such as [K comparable, V any]
+func (pc *packageContext) typeParamsToString(typeParams *ast.FieldList) string {
+	if typeParams == nil || len(typeParams.List) == 0 {
+		return ""
+	}
+
+	var groups []string
+	for _, field := range typeParams.List {
+		names := make([]string, 0, len(field.Names))
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+		groups = append(groups, fmt.Sprintf("%s %s", strings.Join(names, ", "), pc.renderType(field.Type)))
+	}
+
+	return fmt.Sprintf("[%s]", strings.Join(groups, ", "))
+}
+
+// typeParams lists a type parameter list one entry per name, so
+// [K, V comparable] gives two parameters with the same constraint
+func (pc *packageContext) typeParams(typeParams *ast.FieldList) []TypeParam {
+	if typeParams == nil {
+		return nil
+	}
+	var params []TypeParam
+	for _, field := range typeParams.List {
+		constraint := pc.renderType(field.Type)
+		for _, name := range field.Names {
+			params = append(params, TypeParam{Name: name.Name, Constraint: constraint})
+		}
+	}
+	return params
+}
+
+// countFields counts the entries of a parameter or result list, expanding
+// grouped names like (a, b int)
+func countFields(fields *ast.FieldList) int {
+	if fields == nil {
+		return 0
+	}
+	count := 0
+	for _, field := range fields.List {
+		count += max(len(field.Names), 1)
+	}
+	return count
+}
+
+// isVariadic reports whether the final parameter of a function is ...T
+func isVariadic(funcType *ast.FuncType) bool {
+	if funcType == nil || funcType.Params == nil || len(funcType.Params.List) == 0 {
+		return false
+	}
+	last := funcType.Params.List[len(funcType.Params.List)-1]
+	_, ok := last.Type.(*ast.Ellipsis)
+	return ok
+}
+
+// getSignature extracts function signature as string, along with the
+// parameters and results it was built from. Each type is rendered whole by
+// renderType, so func types nest to any depth, e.g. the yield callback of
+// func(yield func(K, V) bool) or iter.Seq2[K, V]
+func (pc *packageContext) getSignature(funcType *ast.FuncType) (string, []ParamInfo, []ParamInfo) {
+	if funcType == nil {
+		return "", nil, nil
+	}
+
+	params := pc.fieldInfos(funcType.Params)
+	results := pc.fieldInfos(funcType.Results)
+
+	sig := pc.typeParamsToString(funcType.TypeParams)
+	sig += fmt.Sprintf("(%s)", joinParams(params)) + joinResults(results)
+
+	return sig, params, results
+}
+
+// fieldInfos expands a parameter or result list, one entry per name
+func (pc *packageContext) fieldInfos(fields *ast.FieldList) []ParamInfo {
+	infos := []ParamInfo{}
+	if fields == nil {
+		return infos
+	}
+	for _, field := range fields.List {
+		typeStr := pc.renderType(field.Type)
+		if len(field.Names) == 0 {
+			infos = append(infos, ParamInfo{Type: typeStr})
+			continue
+		}
+		for _, name := range field.Names {
+			infos = append(infos, ParamInfo{Name: name.Name, Type: typeStr})
+		}
+	}
+	return infos
+}
+
+// joinParams renders parameters as "name type" (or just "type") separated by commas
+func joinParams(infos []ParamInfo) string {
+	parts := make([]string, len(infos))
+	for i, info := range infos {
+		parts[i] = info.Type
+		if info.Name != "" {
+			parts[i] = info.Name + " " + info.Type
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// joinResults renders a result list as gofmt prints it after the
+// parameters: a single unnamed result bare, others parenthesized, and
+// nothing when there are none
+func joinResults(results []ParamInfo) string {
+	switch {
+	case len(results) == 0:
+		return ""
+	case len(results) == 1 && results[0].Name == "":
+		return " " + results[0].Type
+	}
+	return " (" + joinParams(results) + ")"
+}
+
+// typeKind classifies the type expression of a type declaration
+func (pc *packageContext) typeKind(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return pc.typeKind(e.X)
+	case *ast.StructType:
+		return "struct"
+	case *ast.InterfaceType:
+		return "interface"
+	case *ast.MapType:
+		return "map"
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return "slice"
+		}
+		return "array"
+	case *ast.ChanType:
+		return "chan"
+	case *ast.FuncType:
+		return "func"
+	case *ast.StarExpr:
+		return "pointer"
+	case *ast.Ident:
+		// Predeclared int, string, ... unless shadowed by the package
+		obj := types.Universe.Lookup(e.Name)
+		if pc.info != nil && pc.info.Uses[e] != nil {
+			obj = pc.info.Uses[e]
+		}
+		if tn, ok := obj.(*types.TypeName); ok {
+			if _, basic := tn.Type().(*types.Basic); basic {
+				return "basic"
+			}
+		}
+	}
+	return "named"
+}
+
+// isBadExpr reports whether expr, after parentheses and pointers, is
+// syntax the parser could not make sense of
+func isBadExpr(expr ast.Expr) bool {
+	switch e := expr.(type) {
+	case nil, *ast.BadExpr:
+		return true
+	case *ast.ParenExpr:
+		return isBadExpr(e.X)
+	case *ast.StarExpr:
+		return isBadExpr(e.X)
+	}
+	return false
+}
+
+// isStruct reports whether a type expression is a struct type
+func isStruct(expr ast.Expr) bool {
+	_, ok := expr.(*ast.StructType)
+	return ok
+}
+
+// isInterface reports whether a type expression is an interface type
+func isInterface(expr ast.Expr) bool {
+	_, ok := expr.(*ast.InterfaceType)
+	return ok
+}
+
+// receiverType returns the receiver's type, with the type parameters of a
+// generic receiver as the method names them (Set[T]), and whether it is a
+// pointer
+func receiverType(fset *token.FileSet, expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return receiverType(fset, e.X)
+	case *ast.StarExpr:
+		name, _ := receiverType(fset, e.X)
+		return name, true
+	}
+	return typeToString(fset, expr), false
+}
+
+// receiverBase strips type parameters from a receiver: Set[T] -> Set
+func receiverBase(receiver string) string {
+	if i := strings.Index(receiver, "["); i >= 0 {
+		return receiver[:i]
+	}
+	return receiver
+}
+
+// embeddedName returns the field name implied by an embedded type (*pkg.T -> T)
+func embeddedName(expr ast.Expr) string {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	}
+	return ""
+}
+
+// packageContext carries the per-package state shared by the extractors
+type packageContext struct {
+	fset       *token.FileSet
+	info       *types.Info    // Type-checker results; nil if unavailable
+	typesPkg   *types.Package // Checked package, for qualifying type names
+	moduleName string
+	importPath string
+	pkgName    string
+	baseDir    string // Directory file paths are reported relative to
+	opts       *options
+}
+
+// qualifier prints types from other packages by package name and types
+// from the package being introspected unqualified. Parameters, results,
+// fields, constants and variables all render through it, so selector types
+// come out as time.Duration or io.Writer everywhere. With
+// --fully-qualified-types every package, including this one, is written as
+// its import path
+func (pc *packageContext) qualifier(pkg *types.Package) string {
+	if pc.opts.fullyQualified {
+		return pkg.Path()
+	}
+	if pkg == pc.typesPkg {
+		return ""
+	}
+	return pkg.Name()
+}
+
+// renderType renders a type expression from its checked type, which
+// resolves dot-imports and inferred types; it falls back to the syntax when
+// type information is missing. Anonymous struct and interface types are
+// written out inline the way go/types does, e.g. struct{N int; Sum float64};
+// signatures get gofmt's spelling, struct{ N int; Sum float64 }, from
+// gofmtSignature
+func (pc *packageContext) renderType(expr ast.Expr) string {
+	if expr == nil {
+		return ""
+	}
+	if ellipsis, ok := expr.(*ast.Ellipsis); ok {
+		return "..." + pc.renderType(ellipsis.Elt)
+	}
+	// Array lengths keep their source form, [sha256.Size]byte rather than
+	// the evaluated [32]byte, however deeply the array is nested: the types
+	// around a sized array are rendered from the syntax
+	if hasArrayLength(expr) {
+		if typeStr, ok := pc.renderSyntax(expr); ok {
+			return typeStr
+		}
+	}
+	if pc.info != nil {
+		if t := pc.info.TypeOf(expr); t != nil && t != types.Typ[types.Invalid] {
+			return pc.typeString(t)
+		}
+	}
+	return pc.normalize(typeToString(pc.fset, expr))
+}
+
+// hasArrayLength reports whether a type expression contains an array with
+// a length, outside of interface literals
+func hasArrayLength(expr ast.Expr) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ArrayType:
+			if n.Len != nil {
+				found = true
+			}
+		case *ast.InterfaceType:
+			return false
+		}
+		return !found
+	})
+	return found
+}
+
+// renderSyntax renders the type expressions renderType walks through to
+// reach a sized array, spelled as types.TypeString would; ok is false for
+// any other node
+func (pc *packageContext) renderSyntax(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return pc.renderType(e.X), true
+	case *ast.ArrayType:
+		switch e.Len.(type) {
+		case nil:
+			return "[]" + pc.renderType(e.Elt), true
+		case *ast.Ellipsis:
+			// [...]T{...} literals only have their evaluated length
+			return "", false
+		}
+		return "[" + typeToString(pc.fset, e.Len) + "]" + pc.renderType(e.Elt), true
+	case *ast.StarExpr:
+		return "*" + pc.renderType(e.X), true
+	case *ast.MapType:
+		return "map[" + pc.renderType(e.Key) + "]" + pc.renderType(e.Value), true
+	case *ast.ChanType:
+		elem := pc.renderType(e.Value)
+		switch e.Dir {
+		case ast.SEND:
+			return "chan<- " + elem, true
+		case ast.RECV:
+			return "<-chan " + elem, true
+		}
+		if strings.HasPrefix(elem, "<-chan") {
+			elem = "(" + elem + ")"
+		}
+		return "chan " + elem, true
+	case *ast.FuncType:
+		params := pc.fieldInfos(e.Params)
+		results := pc.fieldInfos(e.Results)
+		return "func(" + joinParams(params) + ")" + joinResults(results), true
+	case *ast.StructType:
+		var fields []string
+		for _, field := range e.Fields.List {
+			typeStr := pc.renderType(field.Type)
+			if field.Tag != nil {
+				if tag, err := strconv.Unquote(field.Tag.Value); err == nil {
+					typeStr += " " + strconv.Quote(tag)
+				}
+			}
+			if len(field.Names) == 0 {
+				fields = append(fields, typeStr)
+			}
+			for _, name := range field.Names {
+				fields = append(fields, name.Name+" "+typeStr)
+			}
+		}
+		return "struct{" + strings.Join(fields, "; ") + "}", true
+	case *ast.IndexExpr:
+		return pc.renderInstance(e.X, []ast.Expr{e.Index})
+	case *ast.IndexListExpr:
+		return pc.renderInstance(e.X, e.Indices)
+	}
+	return "", false
+}
+
+// renderInstance renders an instantiated generic type such as
+// Set[[16]byte], naming the generic type as the checked types do
+func (pc *packageContext) renderInstance(generic ast.Expr, args []ast.Expr) (string, bool) {
+	ident, ok := generic.(*ast.Ident)
+	if sel, isSel := generic.(*ast.SelectorExpr); isSel {
+		ident, ok = sel.Sel, true
+	}
+	if !ok || pc.info == nil || pc.info.Uses[ident] == nil {
+		return "", false
+	}
+	obj := pc.info.Uses[ident]
+	name := obj.Name()
+	if obj.Pkg() != nil {
+		if qualifier := pc.qualifier(obj.Pkg()); qualifier != "" {
+			name = qualifier + "." + name
+		}
+	}
+	typeArgs := make([]string, len(args))
+	for i, arg := range args {
+		typeArgs[i] = pc.renderType(arg)
+	}
+	return name + "[" + strings.Join(typeArgs, ", ") + "]", true
+}
+
+// typeString renders a checked type relative to the current package
+func (pc *packageContext) typeString(t types.Type) string {
+	return pc.normalize(types.TypeString(t, pc.qualifier))
+}
+
+// normalize applies cosmetic rendering options: with --normalize-any, empty
+// interfaces are spelled any
+func (pc *packageContext) normalize(typeStr string) string {
+	if pc.opts.normalizeAny {
+		typeStr = strings.ReplaceAll(typeStr, "interface{}", "any")
+	}
+	return typeStr
+}
+
+// objectType renders the checked type of a declared name, or "" when it is
+// unknown or an untyped constant
+func (pc *packageContext) objectType(name *ast.Ident) string {
+	if pc.info == nil {
+		return ""
+	}
+	obj := pc.info.Defs[name]
+	if obj == nil || obj.Type() == types.Typ[types.Invalid] {
+		return ""
+	}
+	if basic, ok := obj.Type().(*types.Basic); ok && basic.Info()&types.IsUntyped != 0 {
+		return ""
+	}
+	return pc.typeString(obj.Type())
+}
+
+// typeSignature renders a type declaration as "type Name = T" for aliases
+// and "type Name T" for definitions. Struct and interface bodies are listed
+// as properties and methods, so only their keyword is shown
+func (pc *packageContext) typeSignature(s *ast.TypeSpec) string {
+	head := "type " + s.Name.Name + pc.typeParamsToString(s.TypeParams)
+	switch {
+	case s.Assign.IsValid():
+		return head + " = " + pc.renderType(s.Type)
+	case isStruct(s.Type):
+		return head + " struct"
+	case isInterface(s.Type):
+		return head + " interface"
+	}
+	return head + " " + pc.renderType(s.Type)
+}
+
+// constValue returns the value of a declared constant as go/types
+// evaluated it, or "" when unknown. Integers, strings and booleans are
+// exact; floats are written as decimals (0.5, not the exact 1/2)
+func (pc *packageContext) constValue(name *ast.Ident) string {
+	if pc.info == nil {
+		return ""
+	}
+	c, ok := pc.info.Defs[name].(*types.Const)
+	if !ok {
+		return ""
+	}
+	switch val := c.Val(); val.Kind() {
+	case constant.Unknown:
+		return ""
+	case constant.Float:
+		f, _ := constant.Float64Val(val)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	case constant.Complex:
+		return val.String()
+	default:
+		return val.ExactString()
+	}
+}
+
+// takesContext reports whether the first parameter (not the receiver) is a context.Context
+func (pc *packageContext) takesContext(funcType *ast.FuncType) bool {
+	if funcType.Params == nil || len(funcType.Params.List) == 0 {
+		return false
+	}
+	return pc.renderType(funcType.Params.List[0].Type) == "context.Context"
+}
+
+// returnsError reports whether the last result, named or not, is an error
+func (pc *packageContext) returnsError(funcType *ast.FuncType) bool {
+	if funcType.Results == nil || len(funcType.Results.List) == 0 {
+		return false
+	}
+	last := funcType.Results.List[len(funcType.Results.List)-1]
+	return pc.renderType(last.Type) == "error"
+}
+
+// optionType returns the option type a functional option function returns,
+// or "". The heuristic: a plain function (no receiver) whose only result is
+// an exported named type, local or qualified, whose name ends in Option or
+// Opt, e.g. func WithTimeout(d time.Duration) Option. Options returned as
+// pointers, interfaces named otherwise, or variadic builders are missed
+func (pc *packageContext) optionType(d *ast.FuncDecl) string {
+	if d.Recv != nil || d.Type.Results == nil || countFields(d.Type.Results) != 1 {
+		return ""
+	}
+	expr := d.Type.Results.List[0].Type
+	var name string
+	switch e := expr.(type) {
+	case *ast.Ident:
+		name = e.Name
+	case *ast.SelectorExpr:
+		name = e.Sel.Name
+	default:
+		return ""
+	}
+	if !isExported(name) || !(strings.HasSuffix(name, "Option") || strings.HasSuffix(name, "Opt")) {
+		return ""
+	}
+	return pc.renderType(expr)
+}
+
+// constructedType returns the exported package type a NewXxx function
+// returns first (T or *T), or "" if it is not a constructor
+func (pc *packageContext) constructedType(d *ast.FuncDecl) string {
+	if d.Recv != nil || !strings.HasPrefix(d.Name.Name, "New") {
+		return ""
+	}
+	if d.Type.Results == nil || len(d.Type.Results.List) == 0 {
+		return ""
+	}
+
+	expr := d.Type.Results.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	// Instantiated generics: New() *Set[T]
+	switch e := expr.(type) {
+	case *ast.IndexExpr:
+		expr = e.X
+	case *ast.IndexListExpr:
+		expr = e.X
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok || !isExported(ident.Name) {
+		return ""
+	}
+	if pc.typesPkg != nil {
+		if _, ok := pc.typesPkg.Scope().Lookup(ident.Name).(*types.TypeName); !ok {
+			return ""
+		}
+	}
+	return ident.Name
+}
+
+// qualifiedPath matches the import path qualifying a name under
+// --fully-qualified-types, example.com/x/pkg in example.com/x/pkg.Type,
+// which is not Go syntax
+var qualifiedPath = regexp.MustCompile(`(?:[\w\-~.]+/)+[\w\-~.]*[\w\-~]\.`)
+
+// gofmtSignature prints a signature the way gofmt would, so spacing and
+// parentheses are byte-stable: callables are reparsed as "func _" + sig,
+// constants, variables and types as declarations and properties as type
+// expressions. Import path qualifiers are swapped for placeholder names
+// while formatting. gofmt spreads structs and interfaces with several
+// members over several lines; those are joined back into its one-line
+// form, struct{ N int; Sum float64 }. Signatures that fail to parse are
+// returned unchanged
+func gofmtSignature(apiType, sig string) string {
+	var paths []string
+	masked := qualifiedPath.ReplaceAllStringFunc(sig, func(path string) string {
+		i := slices.Index(paths, path)
+		if i < 0 {
+			i = len(paths)
+			paths = append(paths, path)
+		}
+		return fmt.Sprintf("_path%d_.", i)
+	})
+
+	fset := token.NewFileSet()
+	var node any
+	prefix, suffix := "", ""
+	switch apiType {
+	case "function", "method":
+		file, err := parser.ParseFile(fset, "", "package p\nfunc _"+masked, parser.SkipObjectResolution)
+		if err != nil || len(file.Decls) != 1 {
+			return sig
+		}
+		node, prefix = file.Decls[0], "func _"
+	case "constant", "variable", "type", "class", "interface", "alias":
+		// Struct and interface types end in their bare keyword
+		if strings.HasSuffix(masked, " struct") || strings.HasSuffix(masked, " interface") {
+			suffix = "{}"
+		}
+		file, err := parser.ParseFile(fset, "", "package p\n"+masked+suffix, parser.SkipObjectResolution)
+		if err != nil || len(file.Decls) != 1 {
+			return sig
+		}
+		node = file.Decls[0]
+	case "property":
+		expr, err := parser.ParseExprFrom(fset, "", masked, parser.SkipObjectResolution)
+		if err != nil {
+			return sig
+		}
+		node = expr
+	default:
+		return sig
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, node); err != nil {
+		return sig
+	}
+	formatted := buf.String()
+	// A newline in sig itself is a raw string constant, kept as written
+	if strings.Contains(formatted, "\n") {
+		if strings.Contains(sig, "\n") {
+			return sig
+		}
+		formatted = joinLines(formatted)
+	}
+	formatted = strings.TrimSuffix(strings.TrimPrefix(formatted, prefix), suffix)
+	for i, path := range paths {
+		formatted = strings.ReplaceAll(formatted, fmt.Sprintf("_path%d_.", i), path)
+	}
+	return formatted
+}
+
+// joinLines folds gofmt's multi-line struct and interface bodies onto one
+// line as gofmt itself writes short ones: members separated by "; " inside
+// "{ " and " }", without the indentation and alignment padding
+func joinLines(formatted string) string {
+	var b strings.Builder
+	prev := ""
+	for i, line := range strings.Split(formatted, "\n") {
+		if i > 0 {
+			line = strings.Join(strings.Fields(line), " ")
+			if strings.HasSuffix(prev, "{") || strings.HasPrefix(line, "}") {
+				b.WriteString(" ")
+			} else {
+				b.WriteString("; ")
+			}
+		}
+		if strings.HasSuffix(line, "struct {") || strings.HasSuffix(line, "interface {") {
+			line = strings.TrimSuffix(line, " {") + "{"
+		}
+		b.WriteString(line)
+		prev = line
+	}
+	return b.String()
+}
+
+// newAPI builds an APIMetadata entry with the fields common to every symbol
+func (pc *packageContext) newAPI(name, apiType string, pos token.Pos, doc *ast.CommentGroup, signature string) APIMetadata {
+	file, line := position(pc.fset, pos, pc.baseDir)
+	start := pc.fset.Position(pos)
+	api := APIMetadata{
+		API:                fmt.Sprintf("%s.%s", pc.importPath, name),
+		Module:             pc.moduleName,
+		ImportPath:         pc.importPath,
+		Package:            pc.pkgName,
+		Type:               apiType,
+		IsAsync:            false, // Go doesn't have async/await
+		HasDocstring:       hasDocstring(doc),
+		Summary:            docSummary(doc),
+		InAll:              true, // Exported
+		Visibility:         "public",
+		IsDeprecated:       isDeprecated(doc),
+		DeprecationMessage: deprecationMessage(doc),
+		IsExperimental:     isExperimental(doc),
+		StableSince:        stableSince(doc),
+		Signature:          gofmtSignature(apiType, signature),
+		CodeBlocks:         docCodeBlocks(doc),
+		File:               file,
+		Line:               line,
+		StartLine:          start.Line,
+		StartCol:           start.Column,
+		EndLine:            start.Line,
+		EndCol:             start.Column,
+	}
+	if pc.opts.includeDocs && doc != nil {
+		api.Doc = doc.Text()
+	}
+	return api
+}
+
+// setSpan records the start and end of the node declaring api
+func (pc *packageContext) setSpan(api *APIMetadata, node ast.Node) {
+	start, end := pc.fset.Position(node.Pos()), pc.fset.Position(node.End())
+	api.StartLine, api.StartCol = start.Line, start.Column
+	api.EndLine, api.EndCol = end.Line, end.Column
+}
+
+// specNode returns the node spanning a spec: the whole declaration when it
+// is ungrouped, so type T struct{...} includes its keyword
+func specNode(spec ast.Spec, decl *ast.GenDecl) ast.Node {
+	if !decl.Lparen.IsValid() {
+		return decl
+	}
+	return spec
+}
+
+// structFields extracts exported struct fields as property APIs
+func (pc *packageContext) structFields(st *ast.StructType, typeName string) []APIMetadata {
+	var apis []APIMetadata
+	if st.Fields == nil {
+		return apis
+	}
+
+	for _, field := range st.Fields.List {
+		names := field.Names
+		if len(names) == 0 {
+			// Embedded field: named after its type
+			names = []*ast.Ident{{Name: embeddedName(field.Type), NamePos: field.Type.Pos()}}
+		}
+
+		for _, name := range names {
+			if !isExported(name.Name) {
+				continue
+			}
+
+			api := pc.newAPI(typeName+"."+name.Name, "property", name.Pos(), field.Doc,
+				pc.renderType(field.Type))
+			pc.setSpan(&api, field)
+			if field.Tag != nil {
+				api.Tag, _ = strconv.Unquote(field.Tag.Value)
+			}
+			apis = append(apis, api)
+		}
+	}
+
+	return apis
+}
+
+// promotedFields lists the exported fields a struct type gains through its
+// embedded fields, resolved with go/types so shadowed and ambiguous names
+// are left out
+func (pc *packageContext) promotedFields(s *ast.TypeSpec) []APIMetadata {
+	if pc.info == nil {
+		return nil
+	}
+	obj, ok := pc.info.Defs[s.Name].(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	var apis []APIMetadata
+	seen := make(map[types.Type]bool)
+	var walk func(embedded types.Type, pos token.Pos)
+	walk = func(embedded types.Type, pos token.Pos) {
+		if ptr, ok := embedded.(*types.Pointer); ok {
+			embedded = ptr.Elem()
+		}
+		inner, ok := embedded.Underlying().(*types.Struct)
+		if !ok || seen[embedded] {
+			return
+		}
+		seen[embedded] = true
+
+		for i := 0; i < inner.NumFields(); i++ {
+			field := inner.Field(i)
+			if field.Exported() && pc.isPromoted(obj.Type(), field) {
+				api := pc.newAPI(s.Name.Name+"."+field.Name(), "property", pos, nil,
+					pc.typeString(field.Type()))
+				api.Tag = inner.Tag(i)
+				api.Promoted = true
+				api.PromotedFrom = pc.typeString(embedded)
+				apis = append(apis, api)
+			}
+			if field.Embedded() {
+				walk(field.Type(), pos)
+			}
+		}
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		if field := st.Field(i); field.Embedded() {
+			// Promoted fields are reported at the embedding field
+			walk(field.Type(), field.Pos())
+		}
+	}
+	return apis
+}
+
+// receiverName names a type as its methods' Receiver does, with the type
+// parameters of a generic type: Set[T]
+func (pc *packageContext) receiverName(s *ast.TypeSpec) string {
+	receiver := s.Name.Name
+	if params := pc.typeParams(s.TypeParams); len(params) > 0 {
+		names := make([]string, len(params))
+		for i, param := range params {
+			names[i] = param.Name
+		}
+		receiver += "[" + strings.Join(names, ", ") + "]"
+	}
+	return receiver
+}
+
+// promotedMethods lists the exported methods a struct type gains through
+// its embedded fields, taken from the method set of *T so methods that need
+// a pointer are included and marked ReceiverIsPointer. They have no doc
+// comment of their own and are reported at the embedding field
+func (pc *packageContext) promotedMethods(s *ast.TypeSpec) []APIMetadata {
+	if pc.info == nil {
+		return nil
+	}
+	obj, ok := pc.info.Defs[s.Name].(*types.TypeName)
+	if !ok {
+		return nil
+	}
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	receiver := pc.receiverName(s)
+	var apis []APIMetadata
+	values := types.NewMethodSet(obj.Type())
+	for sel := range types.NewMethodSet(types.NewPointer(obj.Type())).Methods() {
+		fn := sel.Obj().(*types.Func)
+		if !fn.Exported() || len(sel.Index()) < 2 {
+			continue
+		}
+		// The selection's type has the embedded type's arguments filled in
+		sig := sel.Type().(*types.Signature)
+		params := pc.tupleInfos(sig.Params(), sig.Variadic())
+		results := pc.tupleInfos(sig.Results(), false)
+		signature := "(" + joinParams(params) + ")" + joinResults(results)
+
+		api := pc.newAPI(s.Name.Name+"."+fn.Name(), "method", st.Field(sel.Index()[0]).Pos(), nil, signature)
+		api.Receiver = receiver
+		api.ReceiverIsPointer = values.Lookup(fn.Pkg(), fn.Name()) == nil
+		api.Promoted = true
+		declaring := fn.Type().(*types.Signature).Recv().Type()
+		if ptr, ok := declaring.(*types.Pointer); ok {
+			declaring = ptr.Elem()
+		}
+		api.PromotedFrom = pc.typeString(declaring)
+		api.IsVariadic = sig.Variadic()
+		api.ParamCount = len(params)
+		api.ResultCount = len(results)
+		api.NoArgs = api.ParamCount == 0 && s.TypeParams == nil
+		api.TakesContext = len(params) > 0 && params[0].Type == "context.Context"
+		api.ReturnsError = len(results) > 0 && results[len(results)-1].Type == "error"
+		api.Params, api.Results = params, results
+		apis = append(apis, api)
+	}
+	return apis
+}
+
+// tupleInfos expands a checked parameter or result list like fieldInfos;
+// the last parameter of a variadic signature is written ...T
+func (pc *packageContext) tupleInfos(tuple *types.Tuple, variadic bool) []ParamInfo {
+	infos := []ParamInfo{}
+	for i := 0; i < tuple.Len(); i++ {
+		v := tuple.At(i)
+		typeStr := pc.typeString(v.Type())
+		if slice, ok := v.Type().(*types.Slice); ok && variadic && i == tuple.Len()-1 {
+			typeStr = "..." + pc.typeString(slice.Elem())
+		}
+		infos = append(infos, ParamInfo{Name: v.Name(), Type: typeStr})
+	}
+	return infos
+}
+
+// isPromoted reports whether selecting field's name on t reaches field
+// through at least one embedded field
+func (pc *packageContext) isPromoted(t types.Type, field *types.Var) bool {
+	obj, index, _ := types.LookupFieldOrMethod(t, true, pc.typesPkg, field.Name())
+	return obj != nil && obj.Pos() == field.Pos() && len(index) > 1
+}
+
+// wellKnownInterfaces are single-method standard interfaces matched by
+// method name and bare signature (parameter types and results, packages by
+// name)
+var wellKnownInterfaces = []struct {
+	name, method, signature string
+}{
+	{"error", "Error", "() string"},
+	{"fmt.Stringer", "String", "() string"},
+	{"fmt.GoStringer", "GoString", "() string"},
+	{"fmt.Formatter", "Format", "(fmt.State, rune)"},
+	{"io.Reader", "Read", "([]byte) (int, error)"},
+	{"io.Writer", "Write", "([]byte) (int, error)"},
+	{"io.Closer", "Close", "() error"},
+	{"io.ReaderFrom", "ReadFrom", "(io.Reader) (int64, error)"},
+	{"io.WriterTo", "WriteTo", "(io.Writer) (int64, error)"},
+	{"json.Marshaler", "MarshalJSON", "() ([]byte, error)"},
+	{"json.Unmarshaler", "UnmarshalJSON", "([]byte) error"},
+	{"encoding.TextMarshaler", "MarshalText", "() ([]byte, error)"},
+	{"encoding.TextUnmarshaler", "UnmarshalText", "([]byte) error"},
+	{"sql.Scanner", "Scan", "(any) error"},
+	{"driver.Valuer", "Value", "() (driver.Value, error)"},
+}
+
+// bareSignature renders a method signature without names, qualifying
+// packages by name: ([]byte) (int, error)
+func bareSignature(sig *types.Signature) string {
+	qualifier := func(pkg *types.Package) string { return pkg.Name() }
+	tupleTypes := func(tuple *types.Tuple, variadic bool) []string {
+		parts := make([]string, tuple.Len())
+		for i := range parts {
+			t := tuple.At(i).Type()
+			if variadic && i == tuple.Len()-1 {
+				parts[i] = "..." + types.TypeString(t.(*types.Slice).Elem(), qualifier)
+				continue
+			}
+			parts[i] = types.TypeString(t, qualifier)
+		}
+		return parts
+	}
+
+	s := "(" + strings.Join(tupleTypes(sig.Params(), sig.Variadic()), ", ") + ")"
+	switch results := tupleTypes(sig.Results(), false); len(results) {
+	case 0:
+	case 1:
+		s += " " + results[0]
+	default:
+		s += " (" + strings.Join(results, ", ") + ")"
+	}
+	return strings.ReplaceAll(s, "interface{}", "any")
+}
+
+// implements lists the well-known interfaces a declared type satisfies,
+// including through promoted methods; those needing a pointer receiver are
+// prefixed with *
+func (pc *packageContext) implements(s *ast.TypeSpec) []string {
+	if pc.info == nil {
+		return nil
+	}
+	obj, ok := pc.info.Defs[s.Name].(*types.TypeName)
+	if !ok || types.IsInterface(obj.Type()) {
+		return nil
+	}
+
+	var names []string
+	for _, known := range wellKnownInterfaces {
+		method, _, _ := types.LookupFieldOrMethod(types.NewPointer(obj.Type()), false, nil, known.method)
+		fn, ok := method.(*types.Func)
+		if !ok || bareSignature(fn.Type().(*types.Signature)) != known.signature {
+			continue
+		}
+		// Value receivers are in the method set of T itself
+		if value, _, _ := types.LookupFieldOrMethod(obj.Type(), false, nil, known.method); value == method {
+			names = append(names, known.name)
+		} else {
+			names = append(names, "*"+known.name)
+		}
+	}
+	return names
+}
+
+// walkTypes calls visit for every named type or alias t mentions, directly
+// or inside composite types such as []*Client or func(Option) error,
+// including type arguments. Named types are not descended into; an alias
+// is expanded when visit returns false for it. Only exported fields and
+// methods of anonymous structs and interfaces count
+func walkTypes(t types.Type, visit func(*types.TypeName) bool) {
+	switch t := t.(type) {
+	case *types.Named, *types.Alias:
+		named := t.(interface {
+			Obj() *types.TypeName
+			TypeArgs() *types.TypeList
+		})
+		if alias, ok := t.(*types.Alias); ok && !visit(alias.Obj()) {
+			walkTypes(alias.Rhs(), visit)
+		} else if !ok {
+			visit(named.Obj())
+		}
+		for arg := range named.TypeArgs().Types() {
+			walkTypes(arg, visit)
+		}
+	case *types.Pointer:
+		walkTypes(t.Elem(), visit)
+	case *types.Slice:
+		walkTypes(t.Elem(), visit)
+	case *types.Array:
+		walkTypes(t.Elem(), visit)
+	case *types.Map:
+		walkTypes(t.Key(), visit)
+		walkTypes(t.Elem(), visit)
+	case *types.Chan:
+		walkTypes(t.Elem(), visit)
+	case *types.Signature:
+		for v := range t.Params().Variables() {
+			walkTypes(v.Type(), visit)
+		}
+		for v := range t.Results().Variables() {
+			walkTypes(v.Type(), visit)
+		}
+	case *types.Struct:
+		for field := range t.Fields() {
+			if field.Exported() || field.Embedded() {
+				walkTypes(field.Type(), visit)
+			}
+		}
+	case *types.Interface:
+		for method := range t.ExplicitMethods() {
+			if method.Exported() {
+				walkTypes(method.Type(), visit)
+			}
+		}
+		for embedded := range t.EmbeddedTypes() {
+			walkTypes(embedded, visit)
+		}
+	}
+}
+
+// walkTypeSurface runs walkTypes over what a declared type exposes: its
+// definition and the signatures of its exported methods
+func walkTypeSurface(obj *types.TypeName, visit func(*types.TypeName) bool) {
+	walkTypes(obj.Type().Underlying(), visit)
+	if named, ok := obj.Type().(*types.Named); ok {
+		for method := range named.Methods() {
+			if method.Exported() {
+				walkTypes(method.Type(), visit)
+			}
+		}
+	}
+}
+
+// dependsOn lists, as API names, the exported types of this module that a
+// type's exported fields and methods mention
+func (pc *packageContext) dependsOn(s *ast.TypeSpec) []string {
+	if pc.info == nil {
+		return nil
+	}
+	obj, ok := pc.info.Defs[s.Name].(*types.TypeName)
+	if !ok {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	walkTypeSurface(obj, func(dep *types.TypeName) bool {
+		if dep == obj || !dep.Exported() || dep.Pkg() == nil || !pc.inModule(dep.Pkg().Path()) {
+			return false
+		}
+		seen[dep.Pkg().Path()+"."+dep.Name()] = true
+		return true
+	})
+
+	deps := make([]string, 0, len(seen))
+	for dep := range seen {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	return deps
+}
+
+// referencedUnexported returns the names of this package's unexported
+// types that its exported functions, variables, constants and types
+// mention (--include-referenced-unexported)
+func (pc *packageContext) referencedUnexported() map[string]bool {
+	if pc.typesPkg == nil {
+		return nil
+	}
+	scope := pc.typesPkg.Scope()
+	names := make(map[string]bool)
+	visit := func(dep *types.TypeName) bool {
+		if dep.Exported() || dep.Pkg() != pc.typesPkg || dep.Parent() != scope {
+			return false
+		}
+		names[dep.Name()] = true
+		return true
+	}
+	for _, name := range scope.Names() {
+		obj := scope.Lookup(name)
+		if !obj.Exported() {
+			continue
+		}
+		if typeName, ok := obj.(*types.TypeName); ok {
+			walkTypeSurface(typeName, visit)
+		} else {
+			walkTypes(obj.Type(), visit)
+		}
+	}
+	return names
+}
+
+// inModule reports whether an import path belongs to the module being introspected
+func (pc *packageContext) inModule(path string) bool {
+	return path == pc.moduleName || strings.HasPrefix(path, pc.moduleName+"/")
+}
+
+// implementers lists the candidate types satisfying an interface, as API
+// names; types that only satisfy it through a pointer are prefixed with *.
+// Empty, generic and constraint interfaces are skipped
+func (pc *packageContext) implementers(s *ast.TypeSpec) []string {
+	if pc.info == nil {
+		return nil
+	}
+	obj, ok := pc.info.Defs[s.Name].(*types.TypeName)
+	if !ok || s.TypeParams != nil {
+		return nil
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok || iface.Empty() || !iface.IsMethodSet() {
+		return nil
+	}
+
+	var names []string
+	for _, candidate := range pc.opts.candidates {
+		name := candidate.Pkg().Path() + "." + candidate.Name()
+		switch {
+		case types.Implements(candidate.Type(), iface):
+			names = append(names, name)
+		case types.Implements(types.NewPointer(candidate.Type()), iface):
+			names = append(names, "*"+name)
+		}
+	}
+	return names
+}
+
+// interfaceMethods extracts exported interface methods and embedded
+// interfaces of the interface type s
+func (pc *packageContext) interfaceMethods(it *ast.InterfaceType, s *ast.TypeSpec) []APIMetadata {
+	var apis []APIMetadata
+	if it.Methods == nil {
+		return apis
+	}
+	typeName, receiver := s.Name.Name, pc.receiverName(s)
+
+	for _, field := range it.Methods.List {
+		if len(field.Names) == 0 {
+			// Embedded interface (type-set terms like ~int | ~string are skipped)
+			name := embeddedName(field.Type)
+			if !isExported(name) {
+				continue
+			}
+
+			api := pc.newAPI(typeName+"."+name, "method", field.Type.Pos(), field.Doc,
+				"embedded "+pc.renderType(field.Type))
+			pc.setSpan(&api, field)
+			api.Receiver = receiver
+			apis = append(apis, api)
+			continue
+		}
+
+		funcType, ok := field.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+
+		for _, name := range field.Names {
+			if !isExported(name.Name) {
+				continue
+			}
+
+			sig, params, results := pc.getSignature(funcType)
+			api := pc.newAPI(typeName+"."+name.Name, "method", name.Pos(), field.Doc, sig)
+			pc.setSpan(&api, field)
+			api.Receiver = receiver
+			api.IsVariadic = isVariadic(funcType)
+			api.ParamCount = countFields(funcType.Params)
+			api.ResultCount = countFields(funcType.Results)
+			// Methods of a generic interface need type arguments first
+			api.NoArgs = api.ParamCount == 0 && s.TypeParams == nil
+			api.TakesContext = pc.takesContext(funcType)
+			api.ReturnsError = pc.returnsError(funcType)
+			api.Params, api.Results = params, results
+			apis = append(apis, api)
+		}
+	}
+
+	return apis
+}
+
+// countUndocumented counts the APIs without a doc comment
+func countUndocumented(apis []APIMetadata) int {
+	undocumented := 0
+	for _, api := range apis {
+		if !api.HasDocstring {
+			undocumented++
+		}
+	}
+	return undocumented
+}
+
+// linkConstants lists each package type's constants on its entry, turning
+// typed const blocks into enum-like value lists. apis must still be in
+// declaration order
+func linkConstants(apis []APIMetadata, importPath string) {
+	values := make(map[string][]string)
+	for _, api := range apis {
+		if api.Type == "constant" && api.ConstType != "" {
+			// --fully-qualified-types spells the package's own types with
+			// their import path too
+			typeName := strings.TrimPrefix(api.ConstType, importPath+".")
+			values[typeName] = append(values[typeName], strings.TrimPrefix(api.API, importPath+"."))
+		}
+	}
+	for i, api := range apis {
+		switch api.Type {
+		case "class", "interface", "type", "alias":
+			apis[i].Values = values[strings.TrimPrefix(api.API, importPath+".")]
+		}
+	}
+}
+
+// shortHash returns the first 12 hex characters of the SHA-256 of data
+func shortHash(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// apiHash hashes the parts of an API that make up its contract, leaving out
+// file and line so moving code does not change it
+func apiHash(api APIMetadata) string {
+	return shortHash(strings.Join([]string{api.API, api.Type, api.Signature, strconv.FormatBool(api.IsDeprecated)}, "\x00"))
+}
+
+// apiTypes lists the values APIMetadata.Type can take
+var apiTypes = []string{"function", "class", "interface", "type", "alias", "method", "property", "constant", "variable"}
+
+// parseTypes turns Options.Types into a set, rejecting unknown names
+func parseTypes(names []string) (map[string]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	known := make(map[string]bool, len(apiTypes))
+	for _, t := range apiTypes {
+		known[t] = true
+	}
+	set := make(map[string]bool)
+	for _, t := range names {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !known[t] {
+			return nil, fmt.Errorf("unknown API type %q (want one of %s)", t, strings.Join(apiTypes, ", "))
+		}
+		set[t] = true
+	}
+	return set, nil
+}
+
+// filterTypes keeps only APIs whose Type is in types; a nil set keeps everything
+func filterTypes(apis []APIMetadata, types map[string]bool) []APIMetadata {
+	if types == nil {
+		return apis
+	}
+	kept := apis[:0]
+	for _, api := range apis {
+		if types[api.Type] {
+			kept = append(kept, api)
+		}
+	}
+	return kept
+}
+
+// filterNames keeps only APIs whose own name (Add for Set.Add) matches re;
+// a nil re keeps everything
+func filterNames(apis []APIMetadata, re *regexp.Regexp) []APIMetadata {
+	if re == nil {
+		return apis
+	}
+	kept := apis[:0]
+	for _, api := range apis {
+		if re.MatchString(memberName(api)) {
+			kept = append(kept, api)
+		}
+	}
+	return kept
+}
+
+// knownOS and knownArch are the GOOS and GOARCH values go/build recognizes
+// as file name suffixes
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+		"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true, "arm64": true,
+		"arm64be": true, "loong64": true, "mips": true, "mipsle": true, "mips64": true,
+		"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true, "s390x": true,
+		"sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// fileNameConstraint returns the constraint implied by a _GOOS, _GOARCH or
+// _GOOS_GOARCH file name suffix, such as linux for open_linux.go, or nil
+func fileNameConstraint(filename string) constraint.Expr {
+	name := strings.TrimSuffix(strings.TrimSuffix(filepath.Base(filename), ".go"), "_test")
+	parts := strings.Split(name, "_")
+	// The suffix only counts after a leading name: linux.go is unconstrained
+	if len(parts) < 2 {
+		return nil
+	}
+	last := parts[len(parts)-1]
+	if len(parts) >= 3 && knownOS[parts[len(parts)-2]] && knownArch[last] {
+		return &constraint.AndExpr{X: &constraint.TagExpr{Tag: parts[len(parts)-2]}, Y: &constraint.TagExpr{Tag: last}}
+	}
+	if knownOS[last] || knownArch[last] {
+		return &constraint.TagExpr{Tag: last}
+	}
+	return nil
+}
+
+// buildConstraint returns the build constraint of a file: its //go:build
+// expression combined with any constraint of its file name, e.g.
+// (amd64 || arm64) && linux for trace_linux.go. It is "" when there is none
+func buildConstraint(file *ast.File, filename string) string {
+	var expr constraint.Expr
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package || expr != nil {
+			break
+		}
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			if parsed, err := constraint.Parse(c.Text); err == nil {
+				expr = parsed
+				break
+			}
+		}
+	}
+	if suffix := fileNameConstraint(filename); suffix != nil {
+		if expr == nil {
+			expr = suffix
+		} else {
+			expr = &constraint.AndExpr{X: expr, Y: suffix}
+		}
+	}
+	if expr == nil {
+		return ""
+	}
+	return expr.String()
+}
+
+// dedupAPIs collapses entries repeated with the same import path, name and
+// signature, as files built for different platforms can produce. The build
+// constraints of the files declaring each API (constraints maps File to
+// them) are listed in BuildTags, since only one target is loaded and the
+// variants for other platforms are never seen
+func dedupAPIs(apis []APIMetadata, constraints map[string]string) []APIMetadata {
+	index := make(map[string]int)
+	kept := apis[:0]
+	for _, api := range apis {
+		key := api.ImportPath + "\x00" + api.API + "\x00" + api.Signature
+		i, seen := index[key]
+		if !seen {
+			i = len(kept)
+			index[key] = i
+			kept = append(kept, api)
+		}
+		tag := constraints[api.File]
+		if tag != "" && !slices.Contains(kept[i].BuildTags, tag) {
+			kept[i].BuildTags = append(kept[i].BuildTags, tag)
+		}
+	}
+	return kept
+}
+
+// countMembers attaches method and field counts to each type entry
+func countMembers(apis []APIMetadata, importPath string) {
+	methods := make(map[string]int)
+	fields := make(map[string]int)
+	for _, api := range apis {
+		switch api.Type {
+		case "method":
+			methods[receiverBase(api.Receiver)]++
+		case "property":
+			// Properties are named Type.Field within the package
+			local := strings.TrimPrefix(api.API, importPath+".")
+			if i := strings.LastIndex(local, "."); i >= 0 {
+				fields[local[:i]]++
+			}
+		}
+	}
+
+	for i, api := range apis {
+		switch api.Type {
+		case "class", "interface", "type":
+			name := strings.TrimPrefix(api.API, importPath+".")
+			apis[i].MethodCount = methods[name]
+			apis[i].FieldCount = fields[name]
+		}
+	}
+}
+
+// ownerType splits an API's name within its package into the type it
+// belongs to and whether it is a member (method or property) of that type.
+// Functions, constants and variables have no owner
+func ownerType(api APIMetadata) (owner string, member bool) {
+	name := strings.TrimPrefix(api.API, api.ImportPath+".")
+	if owner, _, ok := strings.Cut(name, "."); ok {
+		return owner, true
+	}
+	switch api.Type {
+	case "class", "interface", "type", "alias":
+		return name, false
+	}
+	return "", false
+}
+
+// sortAPIs orders APIs for stable output by ImportPath, then keeps each
+// type and its members contiguous: functions, constants and variables come
+// first, then every type directly followed by its methods and properties,
+// each run ordered by (Type, API)
+func sortAPIs(apis []APIMetadata) {
+	sort.SliceStable(apis, func(i, j int) bool {
+		a, b := apis[i], apis[j]
+		if a.ImportPath != b.ImportPath {
+			return a.ImportPath < b.ImportPath
+		}
+		ownerA, memberA := ownerType(a)
+		ownerB, memberB := ownerType(b)
+		if ownerA != ownerB {
+			return ownerA < ownerB
+		}
+		if memberA != memberB {
+			return !memberA
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.API < b.API
+	})
+}
+
+// sortedFiles returns a package's syntax trees ordered by file name. Files
+// carrying the "// Code generated ... DO NOT EDIT." header are dropped
+// unless includeGenerated is set.
+func sortedFiles(fset *token.FileSet, files []*ast.File, includeGenerated bool) []*ast.File {
+	var sorted []*ast.File
+	for _, file := range files {
+		if includeGenerated || !ast.IsGenerated(file) {
+			sorted = append(sorted, file)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return fset.Position(sorted[i].Package).Filename < fset.Position(sorted[j].Package).Filename
+	})
+	return sorted
+}
+
+// exampleTarget maps an example name (the part after "Example") to the
+// symbol it documents: Foo, Type_Method and Foo_suffix become Foo,
+// Type.Method and Foo. Package examples map to ""
+func exampleTarget(name string) string {
+	parts := strings.Split(name, "_")
+	if last := parts[len(parts)-1]; len(parts) > 1 && last != "" && !isExported(last) {
+		// Lowercase trailing part is a suffix distinguishing examples
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, ".")
+}
+
+// exampleSource renders an example's code, unwrapping function bodies
+func exampleSource(fset *token.FileSet, ex *doc.Example) string {
+	var buf bytes.Buffer
+	node := &printer.CommentedNode{Node: ex.Code, Comments: ex.Comments}
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	if _, ok := ex.Code.(*ast.BlockStmt); !ok {
+		return buf.String()
+	}
+
+	body := strings.TrimSuffix(strings.TrimPrefix(buf.String(), "{"), "}")
+	lines := strings.Split(strings.Trim(body, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// packageExamples parses the *_test.go files in dir and returns the source
+// of each Example function keyed by the symbol it documents. Files of both
+// the package itself and its external foo_test package are read; test
+// packages are never loaded as packages, so their other declarations stay
+// out of the API list. Examples are extras, so a test file that does not
+// parse is skipped rather than failing the package
+func packageExamples(fset *token.FileSet, dir string) (map[string][]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*_test.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var files []*ast.File
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	examples := make(map[string][]string)
+	for _, ex := range doc.Examples(files...) {
+		if target := exampleTarget(ex.Name); target != "" {
+			examples[target] = append(examples[target], exampleSource(fset, ex))
+		}
+	}
+	return examples, nil
+}
+
+// attachExamples adds each package example to the API it documents
+func attachExamples(apis []APIMetadata, importPath string, examples map[string][]string) {
+	for i := range apis {
+		local := strings.TrimPrefix(apis[i].API, importPath+".")
+		apis[i].Examples = examples[local]
+	}
+}
+
+// countDeclarations counts the top-level functions, methods, types,
+// constants and variables of files, and how many of them are exported
+func countDeclarations(files []*ast.File) (declared, exported int) {
+	count := func(name string) {
+		if name == "_" {
+			return
+		}
+		declared++
+		if isExported(name) {
+			exported++
+		}
+	}
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				count(d.Name.Name)
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						count(s.Name.Name)
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							count(name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+	return declared, exported
+}
+
+// packageDoc aggregates the package comments of all files, in file name order
+func packageDoc(files []*ast.File) string {
+	var docs []string
+	for _, file := range files {
+		if file.Doc != nil {
+			docs = append(docs, file.Doc.Text())
+		}
+	}
+	return strings.Join(docs, "\n")
+}
+
+// introspectPackage introspects a single Go package
+func introspectPackage(pkg *packages.Package, moduleName string, opts *options) (PackageAPIs, error) {
+	if err := packageError(pkg); err != nil {
+		return PackageAPIs{}, err
+	}
+
+	baseDir, err := os.Getwd()
+	if err != nil {
+		return PackageAPIs{}, err
+	}
+
+	if opts.workspace && pkg.Module != nil {
+		moduleName = pkg.Module.Path
+	}
+
+	fset := pkg.Fset
+	files := sortedFiles(fset, pkg.Syntax, opts.includeGenerated)
+	pc := &packageContext{
+		fset:       fset,
+		info:       pkg.TypesInfo,
+		typesPkg:   pkg.Types,
+		moduleName: moduleName,
+		importPath: pkg.PkgPath,
+		pkgName:    pkg.Name,
+		baseDir:    baseDir,
+		opts:       opts,
+	}
+	var referenced map[string]bool
+	if opts.includeReferenced {
+		referenced = pc.referencedUnexported()
+	}
+
+	var apis []APIMetadata
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				// Function or method
+				if !isExported(d.Name.Name) {
+					continue
+				}
+
+				apiType := "function"
+				apiName := d.Name.Name
+				pos := d.Pos()
+				var receiver string
+				var pointer bool
+
+				// Check if it's a method (has receiver)
+				if d.Recv != nil {
+					// Methods whose receiver did not parse have no type to belong to
+					if len(d.Recv.List) == 0 || isBadExpr(d.Recv.List[0].Type) {
+						continue
+					}
+					apiType = "method"
+					pos = d.Name.Pos()
+					// Named after the base type alone, whatever the receiver
+					// form: (s Set[T]), (c *Client) and (r Reader) give
+					// Set.Add, Client.Do and Reader.Read
+					receiver, pointer = receiverType(fset, d.Recv.List[0].Type)
+					// Methods of unexported types are only reachable through
+					// embedding, where promotedMethods reports them
+					if !isExported(receiverBase(receiver)) {
+						continue
+					}
+					apiName = receiverBase(receiver) + "." + d.Name.Name
+				}
+
+				sig, params, results := pc.getSignature(d.Type)
+				api := pc.newAPI(apiName, apiType, pos, d.Doc, sig)
+				pc.setSpan(&api, d)
+				api.Receiver, api.ReceiverIsPointer = receiver, pointer
+				api.IsGeneric = d.Type.TypeParams != nil
+				api.TypeParams = pc.typeParams(d.Type.TypeParams)
+				api.IsVariadic = isVariadic(d.Type)
+				api.ParamCount = countFields(d.Type.Params)
+				api.ResultCount = countFields(d.Type.Results)
+				// Generic functions and methods on generic types need type arguments first
+				api.NoArgs = api.ParamCount == 0 && !api.IsGeneric && !strings.Contains(receiver, "[")
+				api.TakesContext = pc.takesContext(d.Type)
+				api.ReturnsError = pc.returnsError(d.Type)
+				api.Params, api.Results = params, results
+				api.Constructs = pc.constructedType(d)
+				api.IsConstructor = api.Constructs != ""
+				api.OptionType = pc.optionType(d)
+				api.IsOption = api.OptionType != ""
+				apis = append(apis, api)
+
+			case *ast.GenDecl:
+				// Type, const, var declarations
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						// Type declaration (struct, interface, etc.)
+						if !isExported(s.Name.Name) && !referenced[s.Name.Name] {
+							continue
+						}
+
+						// Structs use "class" for consistency with other languages
+						apiType := "type"
+						switch {
+						case s.Assign.IsValid():
+							apiType = "alias"
+						case isStruct(s.Type):
+							apiType = "class"
+						case isInterface(s.Type):
+							apiType = "interface"
+						}
+
+						api := pc.newAPI(s.Name.Name, apiType, s.Name.Pos(), specDoc(s.Doc, d),
+							pc.typeSignature(s))
+						pc.setSpan(&api, specNode(s, d))
+						api.IsGeneric = s.TypeParams != nil
+						api.TypeParams = pc.typeParams(s.TypeParams)
+						if pc.opts.emitDeps {
+							api.DependsOn = pc.dependsOn(s)
+						}
+						api.Kind = pc.typeKind(s.Type)
+						if apiType == "interface" {
+							api.Implementers = pc.implementers(s)
+						} else {
+							api.Implements = pc.implements(s)
+						}
+						if !isExported(s.Name.Name) {
+							// Described so exported signatures can be followed;
+							// its fields and methods stay out of the surface
+							api.InAll = false
+							api.Visibility = "private"
+							api.ReferencedByPublic = true
+							apis = append(apis, api)
+							continue
+						}
+						apis = append(apis, api)
+
+						// Struct fields become properties of the type
+						if st, ok := s.Type.(*ast.StructType); ok {
+							apis = append(apis, pc.structFields(st, s.Name.Name)...)
+							apis = append(apis, pc.promotedFields(s)...)
+							if pc.opts.promotedMethods {
+								apis = append(apis, pc.promotedMethods(s)...)
+							}
+						}
+
+						// Interface method sets become methods of the type
+						if it, ok := s.Type.(*ast.InterfaceType); ok {
+							apis = append(apis, pc.interfaceMethods(it, s)...)
+						}
+
+					case *ast.ValueSpec:
+						// Constant or variable declaration (single or grouped)
+						keyword, apiType := "const", "constant"
+						if d.Tok == token.VAR {
+							keyword, apiType = "var", "variable"
+						}
+
+						for i, name := range s.Names {
+							if !isExported(name.Name) {
+								continue
+							}
+
+							var value ast.Expr
+							if i < len(s.Values) {
+								value = s.Values[i]
+							}
+
+							// Prefer the checked type, which covers implicit types
+							// such as var Default = New() or iota carry-over
+							checked := pc.objectType(name)
+							typeStr := checked
+							// unless a sized array would lose its source length
+							typeExpr := s.Type
+							if typeExpr == nil {
+								typeExpr = literalType(value)
+							}
+							if typeExpr != nil && hasArrayLength(typeExpr) {
+								typeStr = pc.renderType(typeExpr)
+							}
+							if typeStr == "" && s.Type != nil {
+								typeStr = pc.renderType(s.Type)
+							}
+							if typeStr == "" && d.Tok == token.VAR {
+								typeStr = inferType(fset, value)
+							}
+
+							sig := fmt.Sprintf("%s %s", keyword, name.Name)
+							if typeStr != "" {
+								sig += " " + typeStr
+							}
+							if value != nil && (d.Tok == token.CONST || typeStr == "") {
+								sig += " = " + typeToString(fset, value)
+							}
+
+							api := pc.newAPI(name.Name, apiType, name.Pos(), specDoc(s.Doc, d), sig)
+							pc.setSpan(&api, specNode(s, d))
+							if d.Tok == token.CONST {
+								api.ConstType = pc.objectType(name)
+								api.Value = pc.constValue(name)
+							} else if s.Type == nil && checked != "" {
+								api.ResolvedType = typeStr
+							}
+							apis = append(apis, api)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	constraints := make(map[string]string)
+	for _, file := range files {
+		name, _ := position(fset, file.Package, baseDir)
+		constraints[name] = buildConstraint(file, fset.Position(file.Package).Filename)
+	}
+	apis = dedupAPIs(apis, constraints)
+	countMembers(apis, pkg.PkgPath)
+	linkConstants(apis, pkg.PkgPath)
+	declared, exported := countDeclarations(files)
+	if opts.includeExamples && len(files) > 0 {
+		dir := filepath.Dir(fset.Position(files[0].Package).Filename)
+		examples, err := packageExamples(fset, dir)
+		if err != nil {
+			return PackageAPIs{}, fmt.Errorf("examples: %w", err)
+		}
+		attachExamples(apis, pkg.PkgPath, examples)
+	}
+	apis = filterTypes(apis, opts.types)
+	apis = filterNames(apis, opts.nameFilter)
+	for i := range apis {
+		apis[i].Hash = apiHash(apis[i])
+	}
+	sortAPIs(apis)
+	return PackageAPIs{
+		ImportPath:        pkg.PkgPath,
+		PackageName:       pkg.Name,
+		Doc:               packageDoc(files),
+		APIs:              apis,
+		Declared:          declared,
+		Exported:          exported,
+		Files:             len(files),
+		UndocumentedCount: countUndocumented(apis),
+	}, nil
+}
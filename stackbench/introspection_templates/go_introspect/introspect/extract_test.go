@@ -0,0 +1,478 @@
+package introspect
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// introspectSource introspects a throwaway module example.com/fx holding
+// src as its only file
+func introspectSource(t *testing.T, src string, opts Options) *IntrospectionOutput {
+	t.Helper()
+	return introspectFiles(t, map[string]string{"fx.go": src}, opts)
+}
+
+// introspectFiles introspects a throwaway module example.com/fx made of
+// files, keyed by name
+func introspectFiles(t *testing.T, files map[string]string, opts Options) *IntrospectionOutput {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module example.com/fx\n\ngo 1.26\n"
+	for name, src := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	output, err := Introspect(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range output.Errors {
+		t.Fatalf("package %s failed: %s", e.ImportPath, e.Message)
+	}
+	return output
+}
+
+// findAPI returns the API named example.com/fx.name, failing the test when
+// there is none
+func findAPI(t *testing.T, output *IntrospectionOutput, name string) APIMetadata {
+	t.Helper()
+	for _, api := range output.APIs {
+		if api.API == "example.com/fx."+name {
+			return api
+		}
+	}
+	t.Fatalf("no API %s", name)
+	return APIMetadata{}
+}
+
+func TestLinkConstants(t *testing.T) {
+	src := `package fx
+
+type Color int
+
+const (
+	Red Color = iota
+	Green
+	Blue
+)
+`
+	for _, qualified := range []bool{false, true} {
+		output := introspectSource(t, src, Options{FullyQualifiedTypes: qualified})
+		values := findAPI(t, output, "Color").Values
+		if want := []string{"Red", "Green", "Blue"}; !slices.Equal(values, want) {
+			t.Errorf("FullyQualifiedTypes=%v: Color values = %q, want %q", qualified, values, want)
+		}
+	}
+}
+
+func TestIteratorSignatures(t *testing.T) {
+	src := `package fx
+
+import "iter"
+
+type Map[K comparable, V any] struct{}
+
+func (m *Map[K, V]) All() iter.Seq2[K, V] { return nil }
+
+func (m *Map[K, V]) Range(yield func(K, V) bool) {}
+
+func Walk(visit func(yield func(string, int) bool) func() error) {}
+
+func Pairs() func(yield func(string, int) bool) { return nil }
+`
+	output := introspectSource(t, src, Options{})
+	tests := []struct {
+		name, signature string
+	}{
+		{"Map.All", "() iter.Seq2[K, V]"},
+		{"Map.Range", "(yield func(K, V) bool)"},
+		{"Walk", "(visit func(yield func(string, int) bool) func() error)"},
+		{"Pairs", "() func(yield func(string, int) bool)"},
+	}
+	for _, tt := range tests {
+		if got := findAPI(t, output, tt.name).Signature; got != tt.signature {
+			t.Errorf("%s signature = %q, want %q", tt.name, got, tt.signature)
+		}
+	}
+	if params := findAPI(t, output, "Map.Range").Params; len(params) != 1 || params[0].Type != "func(K, V) bool" {
+		t.Errorf("Map.Range params = %+v, want yield func(K, V) bool", params)
+	}
+}
+
+func TestReceiverAndImportEdgeCases(t *testing.T) {
+	src := `package fx
+
+import . "fmt"
+
+type Client struct{}
+
+func (c (*Client)) Do() {}
+
+func (_ Client) Blank() {}
+
+func Show(s Stringer) {}
+
+var _ = Sprint
+`
+	output := introspectSource(t, src, Options{})
+	do := findAPI(t, output, "Client.Do")
+	if do.Receiver != "Client" || !do.ReceiverIsPointer {
+		t.Errorf("Client.Do receiver = %q (pointer %v), want Client (pointer true)", do.Receiver, do.ReceiverIsPointer)
+	}
+	if blank := findAPI(t, output, "Client.Blank"); blank.Receiver != "Client" || blank.ReceiverIsPointer {
+		t.Errorf("Client.Blank receiver = %q (pointer %v), want Client (pointer false)", blank.Receiver, blank.ReceiverIsPointer)
+	}
+	if got := findAPI(t, output, "Show").Signature; got != "(s fmt.Stringer)" {
+		t.Errorf("Show signature = %q, want (s fmt.Stringer)", got)
+	}
+	for _, api := range output.APIs {
+		if strings.HasSuffix(api.API, "._") {
+			t.Errorf("unexpected API %s", api.API)
+		}
+		if strings.Contains(api.Signature, "&{") || strings.Contains(api.Receiver, "&{") {
+			t.Errorf("%s renders an AST node: %q", api.API, api.Signature)
+		}
+	}
+}
+
+func TestIntrospectPackageWithoutTypes(t *testing.T) {
+	src := `package fx
+
+type Client struct{ Name string }
+
+func (c (*Client)) Do(n int) error { return nil }
+
+func () Orphan() {}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "fx.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An empty receiver list is not valid syntax, but must not panic
+	file.Decls = append(file.Decls, &ast.FuncDecl{
+		Recv: &ast.FieldList{},
+		Name: ast.NewIdent("Empty"),
+		Type: &ast.FuncType{Params: &ast.FieldList{}},
+	})
+	pkg := &packages.Package{PkgPath: "example.com/fx", Name: "fx", Fset: fset, Syntax: []*ast.File{file}}
+
+	result, err := introspectPackage(pkg, "example.com/fx", &options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, api := range result.APIs {
+		names = append(names, api.API)
+	}
+	want := []string{"example.com/fx.Client", "example.com/fx.Client.Do", "example.com/fx.Client.Name"}
+	if !slices.Equal(names, want) {
+		t.Errorf("APIs = %q, want %q", names, want)
+	}
+}
+
+func TestMethodNames(t *testing.T) {
+	src := `package fx
+
+type Set[T comparable] map[T]struct{}
+
+func (s Set[T]) Add(v T) {}
+
+func (s *Set[T]) Clear() {}
+
+type Client struct{}
+
+func (c *Client) Do() {}
+
+type Reader struct{}
+
+func (r Reader) Read(p []byte) (int, error) { return 0, nil }
+`
+	output := introspectSource(t, src, Options{})
+	tests := []struct {
+		name     string
+		receiver string
+		pointer  bool
+	}{
+		{"Set.Add", "Set[T]", false},
+		{"Set.Clear", "Set[T]", true},
+		{"Client.Do", "Client", true},
+		{"Reader.Read", "Reader", false},
+	}
+	for _, tt := range tests {
+		api := findAPI(t, output, tt.name)
+		if api.Receiver != tt.receiver || api.ReceiverIsPointer != tt.pointer {
+			t.Errorf("%s receiver = %q (pointer %v), want %q (pointer %v)", tt.name, api.Receiver, api.ReceiverIsPointer, tt.receiver, tt.pointer)
+		}
+	}
+}
+
+func TestGenericNoArgs(t *testing.T) {
+	src := `package fx
+
+type Getter[T any] interface {
+	Get() T
+}
+
+type Box[T any] struct{ v T }
+
+func (b Box[T]) Get() T { return b.v }
+
+type Closer interface {
+	Close() error
+}
+`
+	output := introspectSource(t, src, Options{})
+	tests := []struct {
+		name     string
+		receiver string
+		noArgs   bool
+	}{
+		{"Getter.Get", "Getter[T]", false},
+		{"Box.Get", "Box[T]", false},
+		{"Closer.Close", "Closer", true},
+	}
+	for _, tt := range tests {
+		api := findAPI(t, output, tt.name)
+		if api.Receiver != tt.receiver || api.NoArgs != tt.noArgs {
+			t.Errorf("%s receiver = %q, no_args = %v; want %q, %v", tt.name, api.Receiver, api.NoArgs, tt.receiver, tt.noArgs)
+		}
+	}
+}
+
+func TestArrayLengths(t *testing.T) {
+	src := `package fx
+
+import "crypto/sha256"
+
+const Size = 16
+
+type Digest [sha256.Size]byte
+
+type Block [16]byte
+
+type Set[T comparable] map[T]struct{}
+
+func Sum(data []byte) [sha256.Size]byte { return sha256.Sum256(data) }
+
+func Sums(m map[string][sha256.Size]byte, f func() [sha256.Size]byte) []*[sha256.Size]byte { return nil }
+
+func Keys(s Set[[Size * 2]byte], c chan<- [4]byte) struct{ ID [Size]byte } { return struct{ ID [Size]byte }{} }
+
+var Table = map[string][sha256.Size]byte{}
+
+var Zero [Size]byte
+
+var Pair = [...]int{1, 2}
+`
+	output := introspectSource(t, src, Options{})
+	tests := []struct {
+		name, signature string
+	}{
+		{"Digest", "type Digest [sha256.Size]byte"},
+		{"Block", "type Block [16]byte"},
+		{"Sum", "(data []byte) [sha256.Size]byte"},
+		{"Sums", "(m map[string][sha256.Size]byte, f func() [sha256.Size]byte) []*[sha256.Size]byte"},
+		{"Keys", "(s Set[[Size * 2]byte], c chan<- [4]byte) struct{ ID [Size]byte }"},
+		{"Table", "var Table map[string][sha256.Size]byte"},
+		{"Zero", "var Zero [Size]byte"},
+		{"Pair", "var Pair [2]int"},
+	}
+	for _, tt := range tests {
+		if got := findAPI(t, output, tt.name).Signature; got != tt.signature {
+			t.Errorf("%s signature = %q, want %q", tt.name, got, tt.signature)
+		}
+	}
+	if got := findAPI(t, output, "Table").ResolvedType; got != "map[string][sha256.Size]byte" {
+		t.Errorf("Table resolved type = %q, want map[string][sha256.Size]byte", got)
+	}
+}
+
+func TestUnexportedReceivers(t *testing.T) {
+	src := `package fx
+
+type hidden struct{ Name string }
+
+func (h hidden) Visible() {}
+
+func (h *hidden) Pointer() {}
+
+func New() hidden { return hidden{} }
+`
+	for _, referenced := range []bool{false, true} {
+		output := introspectSource(t, src, Options{IncludeReferencedUnexported: referenced})
+		for _, api := range output.APIs {
+			if strings.HasPrefix(api.API, "example.com/fx.hidden.") {
+				t.Errorf("IncludeReferencedUnexported=%v: unexpected member %s", referenced, api.API)
+			}
+		}
+		if referenced {
+			if hidden := findAPI(t, output, "hidden"); hidden.Visibility != "private" || hidden.InAll {
+				t.Errorf("hidden visibility = %q (in_all %v), want private (in_all false)", hidden.Visibility, hidden.InAll)
+			}
+		}
+	}
+}
+
+func TestExamplesSkipBrokenTestFiles(t *testing.T) {
+	files := map[string]string{
+		"fx.go": `package fx
+
+// Hello greets.
+func Hello() string { return "hello" }
+`,
+		"example_test.go": `package fx_test
+
+import "example.com/fx"
+
+func ExampleHello() {
+	fx.Hello()
+}
+`,
+		"broken_test.go": "package fx\n\nfunc {\n",
+	}
+	output := introspectFiles(t, files, Options{IncludeExamples: true})
+	if examples := findAPI(t, output, "Hello").Examples; len(examples) != 1 {
+		t.Errorf("Hello examples = %q, want the one from example_test.go", examples)
+	}
+}
+
+func TestConstValues(t *testing.T) {
+	src := `package fx
+
+type Level int
+
+const (
+	Debug Level = iota
+	Info
+	Warn
+)
+
+const Pi = 3.14159
+
+const Ratio float64 = 0.5
+
+const Third = 1.0 / 3
+
+const Greeting = "hello"
+
+const Big = 1 << 40
+`
+	output := introspectSource(t, src, Options{})
+	tests := []struct {
+		name, value string
+	}{
+		{"Debug", "0"},
+		{"Warn", "2"},
+		{"Pi", "3.14159"},
+		{"Ratio", "0.5"},
+		{"Third", "0.3333333333333333"},
+		{"Greeting", `"hello"`},
+		{"Big", "1099511627776"},
+	}
+	for _, tt := range tests {
+		if got := findAPI(t, output, tt.name).Value; got != tt.value {
+			t.Errorf("%s value = %q, want %q", tt.name, got, tt.value)
+		}
+	}
+}
+
+func TestBuildTags(t *testing.T) {
+	files := map[string]string{
+		"open.go": "package fx\n\nfunc Close() {}\n",
+		"open_linux.go": `package fx
+
+// Open opens on linux.
+func Open() error { return nil }
+`,
+		"open_windows.go": `package fx
+
+// Open opens on windows.
+func Open() error { return nil }
+`,
+		"trace_linux_amd64.go": `//go:build !race
+
+package fx
+
+func Trace() {}
+`,
+	}
+	tests := []struct {
+		goos string
+		name string
+		want []string
+	}{
+		{"linux", "Open", []string{"linux"}},
+		{"windows", "Open", []string{"windows"}},
+		{"linux", "Close", nil},
+		{"linux", "Trace", []string{"!race && linux && amd64"}},
+	}
+	for _, tt := range tests {
+		output := introspectFiles(t, maps.Clone(files), Options{GOOS: tt.goos, GOARCH: "amd64"})
+		if got := findAPI(t, output, tt.name).BuildTags; !slices.Equal(got, tt.want) {
+			t.Errorf("GOOS=%s: %s build tags = %q, want %q", tt.goos, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGofmtSignatures(t *testing.T) {
+	src := `package fx
+
+import "time"
+
+type Pair struct{ A, B int }
+
+type Set[T comparable] struct{}
+
+type Handler func(s struct{A int}) (error)
+
+type Waits = map[string]struct{D time.Duration; N int}
+
+func Take(s struct{ A int }) {}
+
+func NewPair() *Pair { return nil }
+
+func Stats() struct{N int; Sum float64} { return struct{N int; Sum float64}{} }
+
+func Split(p Pair) (a, b int) { return p.A, p.B }
+
+var Cfg struct{Name string; Tags map[string]string}
+`
+	tests := []struct {
+		qualified       bool
+		name, signature string
+	}{
+		{false, "Set", "type Set[T comparable] struct"},
+		{false, "Handler", "type Handler func(s struct{ A int }) error"},
+		{false, "Waits", "type Waits = map[string]struct{ D time.Duration; N int }"},
+		{false, "Take", "(s struct{ A int })"},
+		{false, "NewPair", "() *Pair"},
+		{false, "Stats", "() struct{ N int; Sum float64 }"},
+		{false, "Split", "(p Pair) (a int, b int)"},
+		{false, "Cfg", "var Cfg struct{ Name string; Tags map[string]string }"},
+		{true, "NewPair", "() *example.com/fx.Pair"},
+		{true, "Split", "(p example.com/fx.Pair) (a int, b int)"},
+		{true, "Waits", "type Waits = map[string]struct{ D time.Duration; N int }"},
+	}
+	outputs := map[bool]*IntrospectionOutput{
+		false: introspectSource(t, src, Options{}),
+		true:  introspectSource(t, src, Options{FullyQualifiedTypes: true}),
+	}
+	for _, tt := range tests {
+		if got := findAPI(t, outputs[tt.qualified], tt.name).Signature; got != tt.signature {
+			t.Errorf("FullyQualifiedTypes=%v: %s signature = %q, want %q", tt.qualified, tt.name, got, tt.signature)
+		}
+	}
+}
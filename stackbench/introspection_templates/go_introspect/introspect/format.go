@@ -0,0 +1,224 @@
+package introspect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// markdownSections orders the top-level sections of a package in Markdown
+// output; methods and properties are listed under their type
+var markdownSections = []struct {
+	title string
+	types []string
+}{
+	{"Constants", []string{"constant"}},
+	{"Variables", []string{"variable"}},
+	{"Functions", []string{"function"}},
+	{"Types", []string{"alias", "class", "interface", "type"}},
+}
+
+// memberName returns the last element of an API's dotted name
+func memberName(api APIMetadata) string {
+	return api.API[strings.LastIndex(api.API, ".")+1:]
+}
+
+// displayName returns an API's name within its package, e.g. Client.Do
+func displayName(api APIMetadata) string {
+	if api.Type == "method" {
+		return receiverBase(api.Receiver) + "." + memberName(api)
+	}
+	return strings.TrimPrefix(api.API, api.ImportPath+".")
+}
+
+// goDeclaration renders an API as the Go source line shown in Markdown
+func goDeclaration(api APIMetadata, inInterface bool) string {
+	name := memberName(api)
+	switch api.Type {
+	case "function":
+		return "func " + name + api.Signature
+	case "method":
+		if inInterface {
+			if strings.HasPrefix(api.Signature, "embedded ") {
+				return strings.TrimPrefix(api.Signature, "embedded ")
+			}
+			return name + api.Signature
+		}
+		recv := api.Receiver
+		if api.ReceiverIsPointer {
+			recv = "*" + recv
+		}
+		return fmt.Sprintf("func (%s) %s%s", recv, name, api.Signature)
+	case "property":
+		return name + " " + api.Signature
+	}
+	return api.Signature
+}
+
+// writeMarkdownAPI writes one API as a heading, its declaration and summary
+func writeMarkdownAPI(w *bytes.Buffer, heading string, api APIMetadata, decl string) {
+	fmt.Fprintf(w, "%s %s\n\n```go\n%s\n```\n\n", heading, displayName(api), decl)
+	if api.IsDeprecated {
+		fmt.Fprintf(w, "**Deprecated:** %s\n\n", api.DeprecationMessage)
+	}
+	if api.Summary != "" {
+		fmt.Fprintf(w, "%s\n\n", api.Summary)
+	}
+}
+
+// writeMarkdownPackage writes the APIs of one package, grouped by kind
+func writeMarkdownPackage(w *bytes.Buffer, apis []APIMetadata, doc string) {
+	fmt.Fprintf(w, "## package %s\n\n`import %q`\n\n", apis[0].Package, apis[0].ImportPath)
+	if doc = strings.TrimSpace(doc); doc != "" {
+		fmt.Fprintf(w, "%s\n\n", doc)
+	}
+
+	// Methods and properties, keyed by the type they belong to
+	members := make(map[string][]APIMetadata)
+	var owners []string
+	for _, api := range apis {
+		owner := ""
+		switch api.Type {
+		case "method":
+			owner = receiverBase(api.Receiver)
+		case "property":
+			owner = strings.SplitN(displayName(api), ".", 2)[0]
+		default:
+			continue
+		}
+		if members[owner] == nil {
+			owners = append(owners, owner)
+		}
+		members[owner] = append(members[owner], api)
+	}
+
+	written := make(map[string]bool)
+	for _, section := range markdownSections {
+		var entries []APIMetadata
+		for _, api := range apis {
+			for _, t := range section.types {
+				if api.Type == t {
+					entries = append(entries, api)
+				}
+			}
+		}
+		if len(entries) == 0 {
+			continue
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].API < entries[j].API })
+
+		fmt.Fprintf(w, "### %s\n\n", section.title)
+		for _, api := range entries {
+			writeMarkdownAPI(w, "####", api, goDeclaration(api, false))
+			if section.title != "Types" {
+				continue
+			}
+			name := displayName(api)
+			written[name] = true
+			// Fields before methods, as in the type's declaration
+			sort.SliceStable(members[name], func(i, j int) bool {
+				return members[name][i].Type == "property" && members[name][j].Type != "property"
+			})
+			for _, member := range members[name] {
+				writeMarkdownAPI(w, "#####", member, goDeclaration(member, api.Type == "interface"))
+			}
+		}
+	}
+
+	// Members whose type is not listed, e.g. methods of unexported types
+	var orphans []APIMetadata
+	for _, owner := range owners {
+		if !written[owner] {
+			orphans = append(orphans, members[owner]...)
+		}
+	}
+	if len(orphans) > 0 {
+		fmt.Fprintf(w, "### Other members\n\n")
+		for _, api := range orphans {
+			writeMarkdownAPI(w, "####", api, goDeclaration(api, false))
+		}
+	}
+}
+
+// encodeMarkdown renders the output as a Markdown API reference, one
+// section per package
+func encodeMarkdown(w *bytes.Buffer, output IntrospectionOutput) {
+	fmt.Fprintf(w, "# %s", output.Library)
+	if output.Version != "" {
+		fmt.Fprintf(w, " %s", output.Version)
+	}
+	fmt.Fprintf(w, "\n\n")
+
+	apis := output.APIs
+	for start := 0; start < len(apis); {
+		end := start
+		for end < len(apis) && apis[end].ImportPath == apis[start].ImportPath {
+			end++
+		}
+		writeMarkdownPackage(w, apis[start:end], output.PackageDocs[apis[start].ImportPath])
+		start = end
+	}
+}
+
+// encodeYAML renders the output as YAML. It goes through the JSON encoding
+// so keys keep their snake_case JSON names and field order
+func encodeYAML(w *bytes.Buffer, output IntrospectionOutput) error {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle clears the flow and quoting styles YAML decoding picked up
+// from the JSON, so the encoder emits block YAML
+func blockStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// Encode writes output to w in format: "json", "yaml" or "markdown".
+// Encoding finishes before anything is written, so a failure never leaves
+// partial output behind. compact skips JSON indentation
+func Encode(w io.Writer, output *IntrospectionOutput, format string, compact bool) error {
+	var buf bytes.Buffer
+	switch format {
+	case "markdown":
+		encodeMarkdown(&buf, *output)
+	case "yaml":
+		if err := encodeYAML(&buf, *output); err != nil {
+			return fmt.Errorf("encode YAML: %w", err)
+		}
+	case "json":
+		encoder := json.NewEncoder(&buf)
+		encoder.SetEscapeHTML(false) // Keep <-chan and & readable in signatures
+		if !compact {
+			encoder.SetIndent("", "  ")
+		}
+		if err := encoder.Encode(output); err != nil {
+			return fmt.Errorf("encode JSON: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown format %q (want json, yaml or markdown)", format)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,593 @@
+// Package introspect extracts the exported API surface of Go packages in
+// the language-agnostic format shared by the stackbench introspection
+// templates (Python, JavaScript, TypeScript, Go, Rust).
+//
+// Packages are loaded with golang.org/x/tools/go/packages, which honors
+// build constraints, and their exported symbols are read with go/ast and
+// go/types. The go_introspect command is a thin wrapper around Introspect.
+package introspect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/types"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// APIMetadata represents a single API in standardized format
+type APIMetadata struct {
+	API                string      `json:"api"`
+	Module             string      `json:"module"`
+	ImportPath         string      `json:"import_path"`
+	Package            string      `json:"package"` // Name from the package clause
+	Type               string      `json:"type"`    // function, class, interface, type, alias, method, property, constant, variable
+	IsAsync            bool        `json:"is_async"`
+	HasDocstring       bool        `json:"has_docstring"`
+	Summary            string      `json:"summary"`              // First sentence of the doc comment
+	Doc                string      `json:"doc,omitempty"`        // Full doc text (--include-docs)
+	InAll              bool        `json:"in_all"`               // Exported (capitalized in Go)
+	Visibility         string      `json:"visibility"`           // public, or private for an unexported type (--include-referenced-unexported)
+	ReferencedByPublic bool        `json:"referenced_by_public"` // Unexported type mentioned by an exported API
+	IsDeprecated       bool        `json:"is_deprecated"`
+	DeprecationMessage string      `json:"deprecation_message"`
+	IsExperimental     bool        `json:"is_experimental"` // Doc comment has an "Experimental:" paragraph
+	StableSince        string      `json:"stable_since"`    // Version from a "Stable since v1.2" line
+	Signature          string      `json:"signature"`
+	Receiver           string      `json:"receiver"`            // Receiver type for methods, with type params: Set[T]
+	ReceiverIsPointer  bool        `json:"receiver_is_pointer"` // Method declared on *T; needs an addressable value
+	IsGeneric          bool        `json:"is_generic"`
+	TypeParams         []TypeParam `json:"type_params,omitempty"` // Generic functions and types: [K comparable] gives {K, comparable}
+	IsVariadic         bool        `json:"is_variadic"`
+	ParamCount         int         `json:"param_count"`            // Callables only; a, b int counts as two
+	ResultCount        int         `json:"result_count"`           // Callables only
+	NoArgs             bool        `json:"no_args"`                // Callables only: callable as F() or v.M(), with no type arguments
+	TakesContext       bool        `json:"takes_context"`          // First parameter is context.Context
+	ReturnsError       bool        `json:"returns_error"`          // Last result is error
+	IsConstructor      bool        `json:"is_constructor"`         // NewXxx returning a type of this package
+	Constructs         string      `json:"constructs"`             // Type built by a constructor
+	IsOption           bool        `json:"is_option"`              // Functional option; see optionType
+	OptionType         string      `json:"option_type"`            // Option type an option function returns
+	MethodCount        int         `json:"method_count"`           // Types only
+	FieldCount         int         `json:"field_count"`            // Types only
+	Kind               string      `json:"kind"`                   // Types only: struct, interface, map, slice, array, chan, func, basic, named, pointer
+	CodeBlocks         []string    `json:"code_blocks,omitempty"`  // Indented code from the doc comment
+	Tag                string      `json:"tag"`                    // Struct tag of a property, unquoted
+	Promoted           bool        `json:"promoted"`               // Property or method reached through an embedded field
+	PromotedFrom       string      `json:"promoted_from"`          // Type that declares a promoted property or method
+	Implementers       []string    `json:"implementers,omitempty"` // Types satisfying an interface (--implementers)
+	Implements         []string    `json:"implements,omitempty"`   // Well-known interfaces a type satisfies, see wellKnownInterfaces
+	DependsOn          []string    `json:"depends_on,omitempty"`   // Exported same-module types used by fields and methods (--emit-deps)
+	ConstType          string      `json:"const_type"`             // Constants only: checked type, following iota carry-over
+	ResolvedType       string      `json:"resolved_type"`          // Variables only: checked type of var X = f(), where none is written
+	Value              string      `json:"value"`                  // Constants only: evaluated value, e.g. 2 for the third iota
+	Hash               string      `json:"hash"`                   // Location-independent content hash, see apiHash
+	BuildTags          []string    `json:"build_tags,omitempty"`   // Build constraints (//go:build and _GOOS/_GOARCH file names) of the declaring files
+	Values             []string    `json:"values,omitempty"`       // Constants of this type, in declaration order
+	Examples           []string    `json:"examples,omitempty"`     // Example function bodies (--include-examples)
+	Params             []ParamInfo `json:"params"`                 // Callables only
+	Results            []ParamInfo `json:"results"`                // Callables only
+	File               string      `json:"file"`
+	Line               int         `json:"line"`
+	StartLine          int         `json:"start_line"` // Span of the declaration; promoted members get the embedding field's position
+	StartCol           int         `json:"start_col"`
+	EndLine            int         `json:"end_line"`
+	EndCol             int         `json:"end_col"`
+}
+
+// ParamInfo describes a single parameter or result of a callable
+type ParamInfo struct {
+	Name string `json:"name"` // Empty when unnamed
+	Type string `json:"type"`
+}
+
+// TypeParam describes a single type parameter of a generic function or type
+type TypeParam struct {
+	Name       string `json:"name"`
+	Constraint string `json:"constraint"` // Rendered like parameter types, e.g. comparable or ~int | ~string
+}
+
+// PackageAPIs groups the APIs of a single package
+type PackageAPIs struct {
+	ImportPath        string        `json:"import_path"`
+	PackageName       string        `json:"package_name"`
+	Doc               string        `json:"doc"` // Package-level doc comment
+	APIs              []APIMetadata `json:"apis"`
+	Declared          int           `json:"total_declared"` // Top-level declarations, exported or not
+	Exported          int           `json:"total_exported"`
+	Files             int           `json:"file_count"`         // Source files scanned
+	UndocumentedCount int           `json:"undocumented_count"` // APIs without a doc comment
+}
+
+// schemaVersion identifies the output format. Bump the minor version when
+// fields are added and the major version when a field changes meaning or
+// is removed, and note what changed below:
+//
+//	1.1   type_params; implements, which later 1.0 output may carry too
+//	1.2   build_tags
+//	1.3   is_experimental, stable_since
+//	1.4   depends_on
+//	1.5   no_args
+//	1.6   visibility, referenced_by_public
+//	1.7   undocumented_count per package
+//	1.8   truncated also set by --max-apis and --max-packages
+//	1.9   promoted methods (promoted now covers methods)
+//	1.10  resolved_type
+//	1.11  start_line, start_col, end_line, end_col
+//	1.12  build_tags on every API of a constrained file, with file name suffixes
+const schemaVersion = "1.12"
+
+// IntrospectionOutput represents the complete output
+type IntrospectionOutput struct {
+	SchemaVersion   string            `json:"schema_version"`
+	Library         string            `json:"library"`
+	Version         string            `json:"version"`
+	Language        string            `json:"language"`
+	GoVersion       string            `json:"go_version"`        // Toolchain that parsed the code
+	ModuleGoVersion string            `json:"module_go_version"` // go directive of the module's go.mod
+	TotalAPIs       int               `json:"total_apis"`
+	APIs            []APIMetadata     `json:"apis,omitempty"` // Left out with --summary-only
+	ByType          map[string]int    `json:"by_type"`
+	DeprecatedCount int               `json:"deprecated_count"`
+	DocumentedCount int               `json:"documented_count"` // APIs with a doc comment
+	PackageCount    int               `json:"package_count"`    // Packages introspected successfully
+	FileCount       int               `json:"file_count"`       // Source files scanned in those packages
+	TotalDeclared   int               `json:"total_declared"`   // Top-level declarations, exported or not
+	TotalExported   int               `json:"total_exported"`
+	PackageDocs     map[string]string `json:"package_docs"`       // Import path -> package doc comment
+	Packages        []PackageAPIs     `json:"packages,omitempty"` // --group-by-package
+	Modules         []string          `json:"modules,omitempty"`  // Workspace modules, when run on a go.work
+	Errors          []PackageError    `json:"errors"`             // Packages that failed to introspect
+	Truncated       bool              `json:"truncated"`          // --timeout, --max-apis or --max-packages cut the output short
+	SurfaceHash     string            `json:"surface_hash"`       // Hash over every API hash; see finish
+
+	hashes []string // API hashes seen by record
+}
+
+// PackageError records a package whose APIs are missing from the output
+type PackageError struct {
+	ImportPath string `json:"import_path"`
+	Message    string `json:"message"`
+}
+
+// Options selects the packages to introspect and what to report about them.
+// The zero value introspects the current directory's package
+type Options struct {
+	Patterns                    []string             // Package patterns; a single module@version fetches that module. Default: go.work modules, else "."
+	Module                      string               // Module name to report; "" reads it from go.mod
+	Version                     string               // Version to report; "auto" detects it from the module or git tags
+	IncludeDocs                 bool                 // Full doc comment text for each API
+	IncludeInternal             bool                 // internal/ and vendor/ packages
+	IncludeGenerated            bool                 // Files marked "Code generated ... DO NOT EDIT."
+	IncludeExamples             bool                 // Example functions from *_test.go files
+	GOOS                        string               // Target platform for build constraints; "" = host
+	GOARCH                      string               // Target architecture; "" = host
+	Tags                        []string             // Build tags to satisfy, e.g. experimental
+	Types                       []string             // API types to keep, e.g. function, method; nil keeps all
+	NameFilter                  *regexp.Regexp       // Symbol names to keep; nil keeps all
+	NormalizeAny                bool                 // Render interface{} as any
+	FullyQualifiedTypes         bool                 // Qualify types by import path instead of package name
+	Implementers                bool                 // List the introspected types implementing each interface
+	EmitDeps                    bool                 // List the same-module types each type depends on
+	IncludePromotedMethods      bool                 // Report methods structs gain through embedded fields
+	IncludeReferencedUnexported bool                 // Also report unexported types that exported APIs mention
+	Timeout                     time.Duration        // Stop after this long and return what is done; 0 = no limit
+	Baseline                    *IntrospectionOutput // Report only APIs missing from it, matched by API and signature
+	MaxAPIs                     int                  // Stop collecting once this many APIs are reported; 0 = no limit
+	MaxPackages                 int                  // Introspect at most this many packages, in import path order; 0 = no limit
+	GroupByPackage              bool                 // Also fill Packages
+	SummaryOnly                 bool                 // Leave out the per-API list
+}
+
+// options controls what the extractors emit
+type options struct {
+	includeDocs       bool
+	includeInternal   bool
+	includeGenerated  bool
+	goos              string // Target platform for build constraints; "" = host
+	goarch            string
+	tags              []string        // Build tags (--tags), passed to the go command
+	types             map[string]bool // API types to keep (--types); nil keeps all
+	includeExamples   bool
+	normalizeAny      bool              // Render interface{} as any
+	workspace         bool              // Report each package's own module (go.work)
+	fullyQualified    bool              // Qualify types by import path instead of package name
+	nameFilter        *regexp.Regexp    // Symbol names to keep (--filter-name); nil keeps all
+	dir               string            // Directory packages are loaded from; "" = current
+	candidates        []*types.TypeName // Named types checked against interfaces (--implementers)
+	emitDeps          bool              // Fill DependsOn for types (--emit-deps)
+	promotedMethods   bool              // Methods reached through embedded fields (--include-promoted-methods)
+	includeReferenced bool              // Unexported types named by exported APIs (--include-referenced-unexported)
+	listOnly          bool              // Resolve package names only, without parsing (ListPackages)
+}
+
+// ErrTruncated is returned by IntrospectStream when opts.Timeout,
+// MaxAPIs or MaxPackages stopped the run before every API was reported
+var ErrTruncated = errors.New("introspect: output truncated")
+
+// errAPILimit stops introspectPackages once MaxAPIs is reached
+var errAPILimit = errors.New("API limit reached")
+
+// Introspect loads the packages selected by opts and returns their API
+// surface. A package that fails to load is recorded in Errors instead of
+// failing the call, and when opts.Timeout expires or MaxAPIs or
+// MaxPackages is reached, what is done so far is returned with Truncated
+// set
+func Introspect(opts Options) (*IntrospectionOutput, error) {
+	var apis []APIMetadata
+	var grouped []PackageAPIs
+	output, err := run(opts, func(result PackageAPIs) error {
+		if opts.SummaryOnly {
+			return nil
+		}
+		apis = append(apis, result.APIs...)
+		if opts.GroupByPackage {
+			grouped = append(grouped, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortAPIs(apis)
+	output.APIs = apis
+	output.Packages = grouped
+	return output, nil
+}
+
+// IntrospectEach calls fn with each package's APIs as the package
+// finishes, in import path order, and returns the summary without them,
+// so memory stays bounded by the packages in flight. The first error fn
+// returns stops the run and is returned
+func IntrospectEach(opts Options, fn func(PackageAPIs) error) (*IntrospectionOutput, error) {
+	return run(opts, fn)
+}
+
+// IntrospectStream calls fn for each API as its package finishes, without
+// holding the whole surface in memory. Packages arrive in import path
+// order, each sorted by name. The first error fn returns stops the run and
+// is returned. Packages that fail to load are skipped and reported in the
+// error returned once the others are done; a run cut short by
+// opts.Timeout, MaxAPIs or MaxPackages returns ErrTruncated. Callers that
+// also want the summary, like the --ndjson CLI mode, use IntrospectEach
+func IntrospectStream(opts Options, fn func(APIMetadata) error) error {
+	output, err := IntrospectEach(opts, func(result PackageAPIs) error {
+		for _, api := range result.APIs {
+			if err := fn(api); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if output.Truncated {
+		return ErrTruncated
+	}
+	if len(output.Errors) > 0 {
+		failed := make([]string, len(output.Errors))
+		for i, e := range output.Errors {
+			failed[i] = e.ImportPath + ": " + e.Message
+		}
+		return fmt.Errorf("%d package(s) failed: %s", len(failed), strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// run introspects the packages selected by opts, handing each package's
+// APIs to emit and returning the summary without them. An error from emit
+// stops the run
+func run(opts Options, emit func(PackageAPIs) error) (*IntrospectionOutput, error) {
+	types, err := parseTypes(opts.Types)
+	if err != nil {
+		return nil, fmt.Errorf("invalid types: %w", err)
+	}
+	o := &options{
+		includeDocs:       opts.IncludeDocs,
+		includeInternal:   opts.IncludeInternal,
+		includeGenerated:  opts.IncludeGenerated,
+		goos:              opts.GOOS,
+		goarch:            opts.GOARCH,
+		tags:              opts.Tags,
+		types:             types,
+		includeExamples:   opts.IncludeExamples,
+		normalizeAny:      opts.NormalizeAny,
+		fullyQualified:    opts.FullyQualifiedTypes,
+		nameFilter:        opts.NameFilter,
+		emitDeps:          opts.EmitDeps,
+		promotedMethods:   opts.IncludePromotedMethods,
+		includeReferenced: opts.IncludeReferencedUnexported,
+	}
+
+	t, err := resolveTarget(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer t.cleanup()
+	o.dir = t.dir
+	o.workspace = t.modules != nil
+	moduleName, version := t.moduleName, t.version
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	pkgs, err := loadPackages(ctx, t.patterns, o)
+	if err != nil && ctx.Err() == nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+	var known map[string]bool
+	if opts.Baseline != nil {
+		known = apiKeys(opts.Baseline.APIs)
+	}
+	truncated := false
+	if opts.MaxPackages > 0 && len(pkgs) > opts.MaxPackages {
+		pkgs, truncated = pkgs[:opts.MaxPackages], true
+	}
+	if opts.Implementers {
+		o.candidates = implementerCandidates(pkgs)
+	}
+	if version == "auto" || version == "-" {
+		version = resolveVersion(pkgs)
+	}
+
+	output := &IntrospectionOutput{
+		Library:       moduleName,
+		Version:       version,
+		SchemaVersion: schemaVersion,
+		Language:      "go",
+		GoVersion:     runtime.Version(),
+		Modules:       t.modules,
+		ByType:        make(map[string]int),
+		PackageDocs:   make(map[string]string),
+		Errors:        []PackageError{},
+		Truncated:     truncated,
+	}
+	if len(pkgs) > 0 && pkgs[0].Module != nil {
+		output.ModuleGoVersion = pkgs[0].Module.GoVersion
+	}
+
+	err = introspectPackages(ctx, pkgs, moduleName, o, func(pkg *packages.Package, result PackageAPIs, err error) error {
+		if err != nil {
+			output.Errors = append(output.Errors, PackageError{ImportPath: pkg.PkgPath, Message: err.Error()})
+			return nil
+		}
+		if known != nil {
+			result.APIs = dropKnown(result.APIs, known)
+			result.UndocumentedCount = countUndocumented(result.APIs)
+		}
+		if remaining := opts.MaxAPIs - output.TotalAPIs; opts.MaxAPIs > 0 && len(result.APIs) > remaining {
+			result.APIs = result.APIs[:remaining]
+			result.UndocumentedCount = countUndocumented(result.APIs)
+			if len(result.APIs) > 0 {
+				output.record(result)
+				if err := emit(result); err != nil {
+					return err
+				}
+			}
+			return errAPILimit
+		}
+		output.record(result)
+		return emit(result)
+	})
+	switch {
+	case ctx.Err() != nil, errors.Is(err, errAPILimit):
+		output.Truncated = true
+	case err != nil:
+		return nil, err
+	}
+
+	output.finish()
+	return output, nil
+}
+
+// target is what Options resolve to before any package is loaded
+type target struct {
+	patterns   []string
+	moduleName string
+	version    string
+	modules    []string // Workspace modules, when run on a go.work
+	dir        string   // Throwaway module of a module@version pattern; see cleanup
+}
+
+// resolveTarget expands the module@version and go.work defaults of
+// opts.Patterns and settles the module name and version to report
+func resolveTarget(opts Options) (*target, error) {
+	moduleName := opts.Module
+	version := opts.Version
+	patterns := opts.Patterns
+	t := &target{}
+	if len(patterns) == 1 && strings.Contains(patterns[0], "@") {
+		// module@version: fetch into the module cache and introspect it there
+		module, _, _ := strings.Cut(patterns[0], "@")
+		dir, resolved, err := prepareRemote(patterns[0])
+		if err != nil {
+			return nil, fmt.Errorf("fetch %s: %w", patterns[0], err)
+		}
+		t.dir = dir
+		patterns = []string{module + "/..."}
+		if moduleName == "" || moduleName == "-" {
+			moduleName = module
+		}
+		if version == "" {
+			version = resolved
+		}
+	}
+	var modules []string
+	if len(patterns) == 0 {
+		// Default to every module of a go.work workspace, else the current directory
+		dirs, err := workspaceModules(".")
+		if err != nil {
+			return nil, fmt.Errorf("read go.work: %w", err)
+		}
+		for _, dir := range dirs {
+			name, err := moduleNameFromGoMod(dir)
+			if err != nil {
+				return nil, fmt.Errorf("read workspace module %s: %w", dir, err)
+			}
+			modules = append(modules, name)
+			patterns = append(patterns, name+"/...")
+		}
+		if len(patterns) == 0 {
+			patterns = []string{"."}
+		}
+	}
+	if modules != nil {
+		if moduleName == "" || moduleName == "-" {
+			wd, err := os.Getwd()
+			if err != nil {
+				return nil, err
+			}
+			moduleName = filepath.Base(wd)
+		}
+	}
+
+	if moduleName == "" || moduleName == "-" {
+		// Explicit names win; otherwise detect from go.mod
+		name, err := moduleNameFromGoMod(strings.TrimSuffix(patterns[0], "..."))
+		if err != nil {
+			return nil, fmt.Errorf("detect module name: %w", err)
+		}
+		moduleName = name
+	}
+
+	t.patterns, t.moduleName, t.version, t.modules = patterns, moduleName, version, modules
+	return t, nil
+}
+
+// cleanup removes the throwaway module of a module@version pattern
+func (t *target) cleanup() {
+	if t.dir != "" {
+		os.RemoveAll(t.dir)
+	}
+}
+
+// ListPackages returns the import paths Introspect would cover for opts,
+// after the internal/ and vendor/ exclusions and opts.MaxPackages, without
+// parsing them
+func ListPackages(opts Options) ([]string, error) {
+	t, err := resolveTarget(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer t.cleanup()
+	o := &options{
+		includeInternal: opts.IncludeInternal,
+		goos:            opts.GOOS,
+		goarch:          opts.GOARCH,
+		tags:            opts.Tags,
+		dir:             t.dir,
+		listOnly:        true,
+	}
+
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	pkgs, err := loadPackages(ctx, t.patterns, o)
+	if err != nil {
+		return nil, fmt.Errorf("load packages: %w", err)
+	}
+	if opts.MaxPackages > 0 && len(pkgs) > opts.MaxPackages {
+		pkgs = pkgs[:opts.MaxPackages]
+	}
+	paths := make([]string, len(pkgs))
+	for i, pkg := range pkgs {
+		paths[i] = pkg.PkgPath
+	}
+	return paths, nil
+}
+
+// introspectPackages runs introspectPackage over a worker pool sized to
+// GOMAXPROCS. Each result is handed to emit in package order as soon as it
+// and every earlier package are done, so callers can stream output. When
+// ctx ends first, or emit returns an error, the remaining packages are
+// abandoned and that error is returned.
+func introspectPackages(ctx context.Context, pkgs []*packages.Package, moduleName string, opts *options, emit func(pkg *packages.Package, result PackageAPIs, err error) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]PackageAPIs, len(pkgs))
+	errs := make([]error, len(pkgs))
+	done := make([]chan struct{}, len(pkgs))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	jobs := make(chan int)
+	for w := 0; w < runtime.GOMAXPROCS(0); w++ {
+		go func() {
+			for i := range jobs {
+				results[i], errs[i] = introspectPackage(pkgs[i], moduleName, opts)
+				close(done[i])
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range pkgs {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for i, pkg := range pkgs {
+		select {
+		case <-done[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if err := emit(pkg, results[i], errs[i]); err != nil {
+			return err
+		}
+		results[i] = PackageAPIs{} // Release for streaming callers
+	}
+	return nil
+}
+
+// record folds a package's APIs into the summary counts
+func (o *IntrospectionOutput) record(pkg PackageAPIs) {
+	o.TotalAPIs += len(pkg.APIs)
+	o.PackageCount++
+	o.FileCount += pkg.Files
+	o.TotalDeclared += pkg.Declared
+	o.TotalExported += pkg.Exported
+	for _, api := range pkg.APIs {
+		o.ByType[api.Type]++
+		if api.IsDeprecated {
+			o.DeprecatedCount++
+		}
+		if api.HasDocstring {
+			o.DocumentedCount++
+		}
+		o.hashes = append(o.hashes, api.Hash)
+	}
+	if pkg.Doc != "" {
+		o.PackageDocs[pkg.ImportPath] = pkg.Doc
+	}
+}
+
+// finish computes the summary fields that need every package: the surface
+// hash covers the sorted API hashes, so it is independent of package order
+func (o *IntrospectionOutput) finish() {
+	sort.Strings(o.hashes)
+	o.SurfaceHash = shortHash(strings.Join(o.hashes, "\n"))
+}
@@ -0,0 +1,68 @@
+package introspect
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestListPackagesMaxPackages(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/fx\n\ngo 1.26\n",
+		"fx.go":  "package fx\n\nfunc A() {}\n",
+		"b/b.go": "package b\n\nfunc B() {}\n",
+		"c/c.go": "package c\n\nfunc C() {}\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Chdir(dir)
+
+	opts := Options{Patterns: []string{"./..."}, MaxPackages: 2}
+	paths, err := ListPackages(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := Introspect(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var introspected []string
+	for _, api := range output.APIs {
+		if !slices.Contains(introspected, api.ImportPath) {
+			introspected = append(introspected, api.ImportPath)
+		}
+	}
+	if want := []string{"example.com/fx", "example.com/fx/b"}; !slices.Equal(paths, want) || !slices.Equal(introspected, want) {
+		t.Errorf("ListPackages = %q, Introspect covered %q; want %q for both", paths, introspected, want)
+	}
+}
+
+func TestMaxAPIsUndocumentedCount(t *testing.T) {
+	src := `package fx
+
+func A() {}
+
+func B() {}
+
+func C() {}
+
+// D is documented.
+func D() {}
+`
+	output := introspectSource(t, src, Options{MaxAPIs: 2, GroupByPackage: true})
+	if len(output.Packages) != 1 {
+		t.Fatalf("got %d packages, want 1", len(output.Packages))
+	}
+	if pkg := output.Packages[0]; len(pkg.APIs) != 2 || pkg.UndocumentedCount != 2 {
+		t.Errorf("package has %d APIs, %d undocumented; want 2 and 2", len(pkg.APIs), pkg.UndocumentedCount)
+	}
+}
@@ -0,0 +1,278 @@
+package introspect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/types"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// findModuleRoot returns the nearest directory at or above dir containing go.mod
+func findModuleRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("go.mod not found")
+		}
+		dir = parent
+	}
+}
+
+// moduleNameFromGoMod finds the nearest go.mod at or above dir and returns its module path
+func moduleNameFromGoMod(dir string) (string, error) {
+	root, err := findModuleRoot(dir)
+	if err != nil {
+		return "", err
+	}
+
+	goMod := filepath.Join(root, "go.mod")
+	data, err := os.ReadFile(goMod)
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[0] != "module" {
+			continue
+		}
+		if name, err := strconv.Unquote(fields[1]); err == nil {
+			return name, nil
+		}
+		return fields[1], nil
+	}
+	return "", fmt.Errorf("no module directive in %s", goMod)
+}
+
+// workspaceModules returns the module directories named by the use
+// directives of dir/go.work, or nil when dir has no go.work
+func workspaceModules(dir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "go.work"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	inUse := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case inUse && fields[0] == ")":
+			inUse = false
+			continue
+		case !inUse && fields[0] == "use" && len(fields) > 1 && fields[1] == "(":
+			inUse = true
+			continue
+		case !inUse && fields[0] == "use" && len(fields) == 2:
+			fields = fields[1:]
+		case !inUse:
+			continue
+		}
+		useDir := fields[0]
+		if unquoted, err := strconv.Unquote(useDir); err == nil {
+			useDir = unquoted
+		}
+		dirs = append(dirs, useDir)
+	}
+	return dirs, nil
+}
+
+// resolveVersion determines the version for -version auto: the version the
+// go command resolved for a dependency module, else the nearest git tag of
+// the module directory, else v0.0.0-unknown
+func resolveVersion(pkgs []*packages.Package) string {
+	dir := "."
+	if len(pkgs) > 0 && pkgs[0].Module != nil {
+		if pkgs[0].Module.Version != "" {
+			return pkgs[0].Module.Version
+		}
+		if pkgs[0].Module.Dir != "" {
+			dir = pkgs[0].Module.Dir
+		}
+	}
+	out, err := exec.Command("git", "-C", dir, "describe", "--tags").Output()
+	if err != nil {
+		return "v0.0.0-unknown"
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// goCommand runs the go command in dir, returning its trimmed stdout or an
+// error carrying its stderr
+func goCommand(dir string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("go %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// prepareRemote creates a throwaway module requiring spec (module@version)
+// so the module's packages load from the module cache. It returns the
+// directory and the version the go command resolved, e.g. for @latest
+func prepareRemote(spec string) (dir, version string, err error) {
+	module, _, _ := strings.Cut(spec, "@")
+	dir, err = os.MkdirTemp("", "go-introspect-")
+	if err != nil {
+		return "", "", err
+	}
+	steps := [][]string{
+		{"mod", "init", "introspection-temp"},
+		{"get", spec},
+	}
+	for _, args := range steps {
+		if _, err := goCommand(dir, args...); err != nil {
+			os.RemoveAll(dir)
+			return "", "", err
+		}
+	}
+	version, err = goCommand(dir, "list", "-m", "-f", "{{.Version}}", module)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", "", err
+	}
+	return dir, version, nil
+}
+
+// isInternalPath reports whether an import path lies in an internal/ or vendor/ tree
+func isInternalPath(importPath string) bool {
+	for _, segment := range strings.Split(importPath, "/") {
+		if segment == "internal" || segment == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizePattern marks bare relative directories (e.g. "sub" or "sub/...")
+// as such, since go/packages would otherwise treat them as import paths
+func normalizePattern(pattern string) string {
+	if strings.HasPrefix(pattern, ".") || filepath.IsAbs(pattern) {
+		return pattern
+	}
+	dir := strings.TrimSuffix(strings.TrimSuffix(pattern, "..."), "/")
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return "./" + pattern
+	}
+	return pattern
+}
+
+// loadPackages resolves package patterns (directories, import paths, "./...")
+// with go/packages, which applies build constraints and real import paths.
+// internal/ and vendor/ packages are dropped unless opts.includeInternal is set.
+func loadPackages(ctx context.Context, patterns []string, opts *options) ([]*packages.Package, error) {
+	normalized := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		normalized[i] = normalizePattern(pattern)
+	}
+
+	cfg := &packages.Config{
+		Mode:    packages.NeedName | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedModule,
+		Env:     os.Environ(),
+		Context: ctx,
+		Dir:     opts.dir,
+	}
+	if opts.listOnly {
+		cfg.Mode = packages.NeedName
+	}
+	// Only files matching the target platform's build constraints are loaded
+	if opts.goos != "" {
+		cfg.Env = append(cfg.Env, "GOOS="+opts.goos)
+	}
+	if opts.goarch != "" {
+		cfg.Env = append(cfg.Env, "GOARCH="+opts.goarch)
+	}
+	if len(opts.tags) > 0 {
+		cfg.BuildFlags = []string{"-tags=" + strings.Join(opts.tags, ",")}
+	}
+	pkgs, err := packages.Load(cfg, normalized...)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*packages.Package
+	for _, pkg := range pkgs {
+		// Not importable by library consumers
+		if !opts.includeInternal && isInternalPath(pkg.PkgPath) {
+			continue
+		}
+		result = append(result, pkg)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].PkgPath < result[j].PkgPath
+	})
+	return result, nil
+}
+
+// packageError summarizes load and parse errors; type errors are tolerated
+// since the syntax is still usable
+func packageError(pkg *packages.Package) error {
+	var messages []string
+	for _, err := range pkg.Errors {
+		if err.Kind == packages.TypeError {
+			continue
+		}
+		messages = append(messages, err.Error())
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+// implementerCandidates collects the exported, non-generic named types of
+// pkgs that can implement an interface
+func implementerCandidates(pkgs []*packages.Package) []*types.TypeName {
+	var candidates []*types.TypeName
+	for _, pkg := range pkgs {
+		if pkg.Types == nil {
+			continue
+		}
+		scope := pkg.Types.Scope()
+		for _, name := range scope.Names() {
+			obj, ok := scope.Lookup(name).(*types.TypeName)
+			if !ok || !obj.Exported() || obj.IsAlias() {
+				continue
+			}
+			named, ok := obj.Type().(*types.Named)
+			if !ok || named.TypeParams() != nil || types.IsInterface(named) {
+				continue
+			}
+			candidates = append(candidates, obj)
+		}
+	}
+	return candidates
+}
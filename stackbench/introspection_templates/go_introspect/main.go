@@ -0,0 +1,424 @@
+/**
+ * Go Library Introspection Script - Language-agnostic output format.
+ *
+ * This command introspects a Go module and outputs a standardized JSON format
+ * that works across all languages (Python, JavaScript, TypeScript, Go, Rust).
+ *
+ * It is a thin command line wrapper around the introspect package, which
+ * Go programs can import to call introspect.Introspect directly, or
+ * introspect.IntrospectStream to handle APIs as they are found.
+ *
+ * Usage (from this directory):
+ *     go run . [-module name] [-version v] [flags] [packages...]
+ *
+ * Run with -h for the full list of flags.
+ *
+ * "go run . diff old.json new.json" compares two outputs and reports
+ * added, removed and changed APIs.
+ *
+ * Packages are go package patterns: directories, import paths, or "./..."
+ * for every package beneath a directory. internal/ and vendor/ packages are
+ * excluded unless --include-internal is given.
+ *
+ * --types function,method restricts output (and the by_type counts) to the
+ * listed API types.
+ *
+ * --tags experimental,debug loads files gated behind those build tags
+ * (//go:build experimental). The tags are handed to the go command through
+ * go/packages, which the introspector always loads with.
+ *
+ * --include-examples attaches testable examples (ExampleFoo,
+ * ExampleType_Method) from the package's *_test.go files, including the
+ * external foo_test package, as "examples".
+ *
+ * --packages-from file reads further patterns from a file, one per line;
+ * blank lines and lines starting with # are skipped.
+ *
+ * --list-packages prints the import paths that would be introspected, one
+ * per line, without parsing them.
+ *
+ * A single module@version argument (golang.org/x/text@v0.14.0) fetches
+ * that module into the module cache and introspects all of its packages.
+ *
+ * Without packages, a go.work in the current directory selects every
+ * module it uses; each API then reports its own module. Otherwise the
+ * current directory is introspected.
+ *
+ * When -module is omitted, the module name is read from the nearest go.mod
+ * above the first package directory.
+ *
+ * Output (stdout, or the file given by -o/--output):
+ *     {
+ *       "library": "github.com/user/lib",
+ *       "version": "v1.0.0",
+ *       "language": "go",
+ *       "total_apis": 42,
+ *       "apis": [...],
+ *       "by_type": {...}
+ *     }
+ *
+ * --format json,markdown --output-dir out writes out/surface.json and
+ * out/surface.md (yaml: surface.yaml) from a single introspection pass.
+ *
+ * --baseline old.json --only-new reports only the APIs whose name and
+ * signature are not in old.json, a previous run's JSON output: the
+ * "what's new" half of diff.
+ *
+ * With --ndjson, each API is written as its own line as packages finish,
+ * followed by a final summary line (the object above without "apis").
+ */
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/moarshy/stackbench-v3/stackbench/introspection_templates/go_introspect/introspect"
+)
+
+// openOutput returns the destination for streamed output: path, or stdout
+// when path is empty
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+// nopCloser keeps stdout open when streaming finishes
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+// writeOutput encodes the output in format to path, or stdout when path is
+// empty. A failed encoding writes nothing
+func writeOutput(output *introspect.IntrospectionOutput, path, format string, compact bool) error {
+	var buf bytes.Buffer
+	if err := introspect.Encode(&buf, output, format, compact); err != nil {
+		return err
+	}
+	if path == "" {
+		_, err := os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// surfaceFiles names the file each format is written to under --output-dir
+var surfaceFiles = map[string]string{
+	"json":     "surface.json",
+	"yaml":     "surface.yaml",
+	"markdown": "surface.md",
+}
+
+// writeSurfaceFiles encodes the output of a single introspection pass once
+// per format into dir, named by surfaceFiles
+func writeSurfaceFiles(output *introspect.IntrospectionOutput, dir string, formats []string, compact bool) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, format := range formats {
+		if err := writeOutput(output, filepath.Join(dir, surfaceFiles[format]), format, compact); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeNDJSON introspects opts, writing each API as its own line as its
+// package finishes and then the summary without the APIs, so the surface
+// is never held in memory
+func writeNDJSON(opts introspect.Options, path string) (*introspect.IntrospectionOutput, error) {
+	out, err := openOutput(path)
+	if err != nil {
+		return nil, fmt.Errorf("open output: %w", err)
+	}
+	defer out.Close()
+	w := bufio.NewWriter(out)
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	output, err := introspect.IntrospectEach(opts, func(result introspect.PackageAPIs) error {
+		if opts.SummaryOnly {
+			return nil
+		}
+		for _, api := range result.APIs {
+			if err := encoder.Encode(api); err != nil {
+				return fmt.Errorf("write output: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := encoder.Encode(output); err != nil {
+		return nil, fmt.Errorf("write output: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("write output: %w", err)
+	}
+	return output, nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// readPatterns reads package patterns from path, one per line, skipping
+// blank lines and # comments
+func readPatterns(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// runDiff implements "diff old.json new.json": the JSON delta goes to
+// stdout and the summary to stderr
+func runDiff(args []string) {
+	if len(args) != 2 {
+		fmt.Fprintln(os.Stderr, "Usage: go run . diff old.json new.json")
+		os.Exit(2)
+	}
+	from, err := introspect.LoadOutput(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to read output: %v\n", err)
+		os.Exit(1)
+	}
+	to, err := introspect.LoadOutput(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to read output: %v\n", err)
+		os.Exit(1)
+	}
+
+	diff := introspect.Diff(from, to)
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(diff); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to write output: %v\n", err)
+		os.Exit(1)
+	}
+	introspect.WriteDiffSummary(os.Stderr, diff)
+}
+
+// usage prints the command line synopsis and flag defaults for -h/--help
+func usage() {
+	fmt.Fprintln(flag.CommandLine.Output(), "Usage: go run . [flags] [packages...]")
+	fmt.Fprintln(flag.CommandLine.Output(), "       go run . diff old.json new.json")
+	fmt.Fprintln(flag.CommandLine.Output(), "\nPackages default to \".\". Flags:")
+	flag.PrintDefaults()
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+
+	flag.Usage = usage
+	moduleFlag := flag.String("module", "", "module name to report (default: read from go.mod)")
+	version := flag.String("version", "", "module version to report; \"auto\" or \"-\" detects it from the module or git tags")
+	includeInternal := flag.Bool("include-internal", false, "include internal/ and vendor/ packages")
+	groupByPackage := flag.Bool("group-by-package", false, "also emit APIs grouped per package")
+	includeDocs := flag.Bool("include-docs", false, "include the full doc comment text for each API")
+	includeGenerated := flag.Bool("include-generated", false, "include files marked \"Code generated ... DO NOT EDIT.\"")
+	var outputPath string
+	flag.StringVar(&outputPath, "o", "", "write JSON to this file instead of stdout")
+	flag.StringVar(&outputPath, "output", "", "write JSON to this file instead of stdout")
+	compact := flag.Bool("compact", false, "emit minified single-line JSON")
+	ndjson := flag.Bool("ndjson", false, "stream one API JSON object per line, followed by a summary object")
+	goos := flag.String("goos", "", "target GOOS for build constraints (default: host)")
+	goarch := flag.String("goarch", "", "target GOARCH for build constraints (default: host)")
+	tagsFlag := flag.String("tags", "", "comma-separated build tags to satisfy, e.g. experimental")
+	includeExamples := flag.Bool("include-examples", false, "attach Example functions from *_test.go files to the APIs they document")
+	normalizeAny := flag.Bool("normalize-any", false, "render empty interfaces as any in signatures")
+	strict := flag.Bool("strict", false, "exit non-zero if any package fails to load or parse")
+	format := flag.String("format", "json", "output format: json, yaml or markdown; several, comma-separated, need --output-dir")
+	outputDir := flag.String("output-dir", "", "write surface.json, surface.yaml or surface.md for each --format into this directory")
+	implementers := flag.Bool("implementers", false, "list the introspected types implementing each interface")
+	includeReferenced := flag.Bool("include-referenced-unexported", false, "also report unexported types mentioned by exported APIs, marked visibility \"private\"")
+	promotedMethods := flag.Bool("include-promoted-methods", false, "also report methods structs gain through embedded fields, marked promoted")
+	emitDeps := flag.Bool("emit-deps", false, "list the exported same-module types each type's fields and methods use")
+	fullyQualified := flag.Bool("fully-qualified-types", false, "qualify types with their full import path, e.g. github.com/x/y/pkg.Type")
+	baselinePath := flag.String("baseline", "", "JSON output of a previous run to compare against (with --only-new)")
+	onlyNew := flag.Bool("only-new", false, "report only APIs whose name and signature are missing from --baseline")
+	maxAPIs := flag.Int("max-apis", 0, "stop collecting after this many APIs and mark the output truncated (default: no limit)")
+	maxPackages := flag.Int("max-packages", 0, "introspect at most this many packages and mark the output truncated (default: no limit)")
+	timeout := flag.Duration("timeout", 0, "abort after this long, emitting the packages done so far (e.g. 2m; default: no limit)")
+	filterName := flag.String("filter-name", "", "regexp the symbol name must match, e.g. ^New (method and field names exclude the type)")
+	summaryOnly := flag.Bool("summary-only", false, "emit only the aggregate counts, without the per-API list")
+	packagesFrom := flag.String("packages-from", "", "read additional package patterns from this file, one per line (# starts a comment)")
+	listPackages := flag.Bool("list-packages", false, "print the import paths that would be introspected and exit without parsing them")
+	typesFlag := flag.String("types", "", "comma-separated API types to emit, e.g. function,method (default: all)")
+	flag.Parse()
+
+	formats := splitList(*format)
+	for _, f := range formats {
+		if surfaceFiles[f] == "" {
+			fmt.Fprintf(os.Stderr, "ERROR: Unknown --format %q (want json, yaml or markdown)\n", f)
+			os.Exit(1)
+		}
+	}
+	if len(formats) == 0 {
+		formats = []string{"json"}
+	}
+	if *ndjson && (len(formats) > 1 || formats[0] != "json" || *outputDir != "") {
+		fmt.Fprintln(os.Stderr, "ERROR: --ndjson only supports --format json, without --output-dir")
+		os.Exit(1)
+	}
+	if len(formats) > 1 && *outputDir == "" {
+		fmt.Fprintln(os.Stderr, "ERROR: Several --format values need --output-dir")
+		os.Exit(1)
+	}
+	if *outputDir != "" && outputPath != "" {
+		fmt.Fprintln(os.Stderr, "ERROR: -o/--output and --output-dir are mutually exclusive")
+		os.Exit(1)
+	}
+
+	types := splitList(*typesFlag)
+
+	var nameFilter *regexp.Regexp
+	if *filterName != "" {
+		var err error
+		nameFilter, err = regexp.Compile(*filterName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Invalid --filter-name: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *onlyNew != (*baselinePath != "") {
+		fmt.Fprintln(os.Stderr, "ERROR: --only-new and --baseline must be given together")
+		os.Exit(1)
+	}
+	var baseline *introspect.IntrospectionOutput
+	if *baselinePath != "" {
+		loaded, err := introspect.LoadOutput(*baselinePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Failed to read --baseline: %v\n", err)
+			os.Exit(1)
+		}
+		baseline = &loaded
+	}
+
+	patterns := flag.Args()
+	if *packagesFrom != "" {
+		extra, err := readPatterns(*packagesFrom)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: Failed to read --packages-from: %v\n", err)
+			os.Exit(1)
+		}
+		patterns = append(patterns, extra...)
+	}
+
+	opts := introspect.Options{
+		Patterns:                    patterns,
+		Module:                      *moduleFlag,
+		Version:                     *version,
+		IncludeDocs:                 *includeDocs,
+		IncludeInternal:             *includeInternal,
+		IncludeGenerated:            *includeGenerated,
+		IncludeExamples:             *includeExamples,
+		GOOS:                        *goos,
+		GOARCH:                      *goarch,
+		Tags:                        splitList(*tagsFlag),
+		Types:                       types,
+		NameFilter:                  nameFilter,
+		NormalizeAny:                *normalizeAny,
+		FullyQualifiedTypes:         *fullyQualified,
+		Implementers:                *implementers,
+		EmitDeps:                    *emitDeps,
+		IncludePromotedMethods:      *promotedMethods,
+		IncludeReferencedUnexported: *includeReferenced,
+		Timeout:                     *timeout,
+		Baseline:                    baseline,
+		MaxAPIs:                     *maxAPIs,
+		MaxPackages:                 *maxPackages,
+		GroupByPackage:              *groupByPackage,
+		SummaryOnly:                 *summaryOnly,
+	}
+
+	if *listPackages {
+		paths, err := introspect.ListPackages(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+			os.Exit(1)
+		}
+		for _, path := range paths {
+			fmt.Println(path)
+		}
+		return
+	}
+
+	// NDJSON is written while packages are introspected; the other
+	// formats need the whole, sorted surface first
+	var output *introspect.IntrospectionOutput
+	var err error
+	if *ndjson {
+		output, err = writeNDJSON(opts, outputPath)
+	} else {
+		output, err = introspect.Introspect(opts)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %v\n", err)
+		os.Exit(1)
+	}
+	for _, e := range output.Errors {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to introspect package %s: %s\n", e.ImportPath, e.Message)
+	}
+	if output.Truncated {
+		fmt.Fprintln(os.Stderr, "WARNING: --timeout, --max-apis or --max-packages was reached; output is truncated")
+	}
+
+	switch {
+	case *ndjson:
+	case *outputDir != "":
+		err = writeSurfaceFiles(output, *outputDir, formats, *compact)
+	default:
+		err = writeOutput(output, outputPath, formats[0], *compact)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: Failed to write output: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *strict && output.Truncated {
+		os.Exit(1)
+	}
+	if *strict && len(output.Errors) > 0 {
+		failed := make([]string, len(output.Errors))
+		for i, e := range output.Errors {
+			failed[i] = e.ImportPath
+		}
+		fmt.Fprintf(os.Stderr, "ERROR: %d package(s) failed: %s\n", len(failed), strings.Join(failed, ", "))
+		os.Exit(1)
+	}
+}
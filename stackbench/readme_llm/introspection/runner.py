@@ -29,7 +29,7 @@ class IntrospectionRunner:
     Handles:
     - Python: Creates venv, pip installs, runs python_introspect.py
     - TypeScript/JavaScript: Creates npm project, installs, runs typescript_introspect.ts
-    - Go: Uses go mod, runs go_introspect.go (Phase 2)
+    - Go: Uses go mod, runs the go_introspect module (Phase 2)
     - Rust: Uses cargo, runs rust_introspect.rs (Phase 2)
     """
 
@@ -285,9 +285,9 @@ class IntrospectionRunner:
         modules: Optional[List[str]] = None
     ) -> IntrospectionResult:
         """
-        Introspect Go library using go_introspect.go template.
+        Introspect Go library using the go_introspect template module.
 
-        Creates go mod project, installs module, runs introspection.
+        Copies the module, adds the library to it, runs introspection.
 
         Args:
             library_name: Go module name (e.g., github.com/user/lib)
@@ -297,23 +297,16 @@ class IntrospectionRunner:
         Returns:
             IntrospectionResult
         """
-        template_path = self.templates_dir / "go_introspect.go"
+        template_path = self.templates_dir / "go_introspect"
         if not template_path.exists():
             raise FileNotFoundError(f"Go template not found: {template_path}")
 
-        # Create temporary Go module
+        # Copy the template module (CLI plus the introspect package); its
+        # go.mod already requires golang.org/x/tools and gopkg.in/yaml.v3
         with tempfile.TemporaryDirectory(prefix="readme_llm_go_") as tmpdir:
-            tmpdir_path = Path(tmpdir)
-            logger.debug(f"Created temporary Go module: {tmpdir}")
-
-            # Initialize Go module
-            logger.debug("Initializing Go module...")
-            subprocess.run(
-                ["go", "mod", "init", "introspection-temp"],
-                cwd=tmpdir_path,
-                check=True,
-                capture_output=True
-            )
+            tmpdir_path = Path(tmpdir) / "go_introspect"
+            shutil.copytree(template_path, tmpdir_path)
+            logger.debug(f"Created temporary Go module: {tmpdir_path}")
 
             # Install library
             module_spec = f"{library_name}@{version}"
@@ -328,13 +321,9 @@ class IntrospectionRunner:
             if result.returncode != 0:
                 raise RuntimeError(f"Failed to install {module_spec}: {result.stderr}")
 
-            # Copy template to temp directory
-            template_copy = tmpdir_path / "introspect.go"
-            shutil.copy(template_path, template_copy)
-
-            # Run introspection
-            modules_args = modules or ["."]
-            cmd = ["go", "run", "introspect.go", library_name, version] + modules_args
+            # Run introspection (default: every package in the library)
+            modules_args = modules or [f"{library_name}/..."]
+            cmd = ["go", "run", ".", "-module", library_name, "-version", version] + modules_args
 
             logger.debug(f"Running introspection: {' '.join(cmd)}")
             result = subprocess.run(
@@ -361,7 +350,7 @@ class IntrospectionRunner:
                 library_version=version,
                 apis=output_data.get("apis", []),
                 timestamp=datetime.now().isoformat(),
-                introspection_method="go/parser",
+                introspection_method="go/packages",
                 total_functions=output_data.get("by_type", {}).get("function", 0),
                 total_classes=output_data.get("by_type", {}).get("class", 0),
                 total_methods=output_data.get("by_type", {}).get("method", 0)